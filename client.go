@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/tigrisdata/storage-go/tigrisheaders"
 )
 
@@ -27,11 +31,40 @@ func (c *Client) CreateBucketFork(ctx context.Context, source, target string, op
 	}, opts...)
 }
 
+// CreateBucketSnapshotOutput is the result of creating a bucket snapshot.
+type CreateBucketSnapshotOutput struct {
+	*s3.CreateBucketOutput
+
+	SnapshotVersion string    // The version ID of the new snapshot, for use with tigrisheaders.WithSnapshotVersion.
+	Created         time.Time // When the server created the snapshot, or the zero time if it did not say.
+}
+
 // CreateBucketSnapshot creates a snapshot with the given description for a bucket.
-func (c *Client) CreateBucketSnapshot(ctx context.Context, description string, in *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+//
+// The version of the new snapshot is read from the response headers, so it can be
+// used right away to fork the bucket or read objects as of the snapshot.
+func (c *Client) CreateBucketSnapshot(ctx context.Context, description string, in *s3.CreateBucketInput, opts ...func(*s3.Options)) (*CreateBucketSnapshotOutput, error) {
 	opts = append(opts, tigrisheaders.WithTakeSnapshot(description))
 
-	return c.Client.CreateBucket(ctx, in, opts...)
+	resp, err := c.Client.CreateBucket(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rawResp, ok := middleware.GetRawResponse(resp.ResultMetadata).(*smithyhttp.Response)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type from middleware")
+	}
+
+	out := &CreateBucketSnapshotOutput{
+		CreateBucketOutput: resp,
+		SnapshotVersion:    rawResp.Header.Get("X-Tigris-Snapshot-Version"),
+	}
+	if date, err := http.ParseTime(rawResp.Header.Get("Date")); err == nil {
+		out.Created = date
+	}
+
+	return out, nil
 }
 
 // CreateSnapshotEnabledBucket creates a new bucket with the ability to take snapshots and fork the contents of it.
@@ -60,7 +93,7 @@ func (c *Client) HeadBucketForkOrSnapshot(ctx context.Context, in *s3.HeadBucket
 		return nil, err
 	}
 
-	rawResp, ok := middleware.GetRawResponse(resp.ResultMetadata).(*http.Response)
+	rawResp, ok := middleware.GetRawResponse(resp.ResultMetadata).(*smithyhttp.Response)
 	if !ok {
 		return nil, fmt.Errorf("unexpected response type from middleware")
 	}
@@ -72,7 +105,8 @@ func (c *Client) HeadBucketForkOrSnapshot(ctx context.Context, in *s3.HeadBucket
 	}, nil
 }
 
-// ListBucketSnapshots lists the snapshots for a bucket.
+// ListBucketSnapshots lists the snapshots for a bucket. Use ParseSnapshotName to get
+// the version and description of each returned entry.
 //
 // For more information, see the Tigris documentation[1].
 //
@@ -83,6 +117,33 @@ func (c *Client) ListBucketSnapshots(ctx context.Context, bucketName string, opt
 	return c.Client.ListBuckets(ctx, &s3.ListBucketsInput{}, opts...)
 }
 
+// ParseSnapshotName splits an entry name from a ListBucketSnapshots response into the
+// snapshot version and the description set with tigrisheaders.WithTakeSnapshot.
+//
+// Tigris reports each snapshot as a bucket named "<version>; name=<url-escaped
+// description>", or just "<version>" for snapshots without a description.
+func ParseSnapshotName(entry string) (version, description string, err error) {
+	version, params, _ := strings.Cut(entry, ";")
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return "", "", fmt.Errorf("storage: malformed snapshot entry %q: no version", entry)
+	}
+
+	for param := range strings.SplitSeq(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || key != "name" {
+			continue
+		}
+
+		description, err = url.QueryUnescape(value)
+		if err != nil {
+			return "", "", fmt.Errorf("storage: malformed snapshot entry %q: %w", entry, err)
+		}
+	}
+
+	return version, description, nil
+}
+
 // RenameObject performs an in-place rename of objects instead of copying the data.
 //
 // For more information, see the Tigris documentation[1].
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+	"github.com/tigrisdata/storage-go/tigristest"
+)
+
+// newTestClient returns a Client backed by an in-process fake Tigris server.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	srv := tigristest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := New(context.Background(),
+		WithEndpoint(srv.URL),
+		WithPathStyle(true),
+		WithAccessKeypair("test", "test"),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return client
+}
+
+func TestClient_CreateBucketFork(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateSnapshotEnabledBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("source")}); err != nil {
+		t.Fatalf("CreateSnapshotEnabledBucket() failed: %v", err)
+	}
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("source"),
+		Key:    aws.String("file.txt"),
+		Body:   strings.NewReader("forked"),
+	}); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+
+	if _, err := client.CreateBucketFork(ctx, "source", "fork"); err != nil {
+		t.Fatalf("CreateBucketFork() failed: %v", err)
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("fork"), Key: aws.String("file.txt")})
+	if err != nil {
+		t.Fatalf("GetObject() from fork failed: %v", err)
+	}
+	defer out.Body.Close()
+	data, _ := io.ReadAll(out.Body)
+	if string(data) != "forked" {
+		t.Errorf("GetObject() from fork = %q, want %q", data, "forked")
+	}
+
+	info, err := client.HeadBucketForkOrSnapshot(ctx, &s3.HeadBucketInput{Bucket: aws.String("fork")})
+	if err != nil {
+		t.Fatalf("HeadBucketForkOrSnapshot() failed: %v", err)
+	}
+	if info.SourceBucket != "source" {
+		t.Errorf("SourceBucket = %q, want source", info.SourceBucket)
+	}
+	if info.SourceBucketSnapshot == "" {
+		t.Error("SourceBucketSnapshot is empty, want the implicit snapshot version")
+	}
+}
+
+func TestClient_CreateBucketFork_requiresSnapshots(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("plain")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	if _, err := client.CreateBucketFork(ctx, "plain", "fork"); err == nil {
+		t.Error("CreateBucketFork() from a bucket without snapshots succeeded, want error")
+	}
+}
+
+func TestClient_CreateBucketSnapshot(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateSnapshotEnabledBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateSnapshotEnabledBucket() failed: %v", err)
+	}
+
+	put := func(body string) {
+		t.Helper()
+		if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("config.json"),
+			Body:   strings.NewReader(body),
+		}); err != nil {
+			t.Fatalf("PutObject() failed: %v", err)
+		}
+	}
+
+	put("v1")
+	before := time.Now().Add(-time.Minute)
+	snap, err := client.CreateBucketSnapshot(ctx, "before upgrade", &s3.CreateBucketInput{Bucket: aws.String("bucket")})
+	if err != nil {
+		t.Fatalf("CreateBucketSnapshot() failed: %v", err)
+	}
+	if snap.SnapshotVersion == "" {
+		t.Fatal("CreateBucketSnapshot() returned no SnapshotVersion")
+	}
+	if snap.Created.Before(before) {
+		t.Errorf("CreateBucketSnapshot() Created = %v, want a current server time", snap.Created)
+	}
+	if snap.CreateBucketOutput == nil {
+		t.Error("CreateBucketSnapshot() dropped the CreateBucket output")
+	}
+	put("v2")
+
+	resp, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("config.json"),
+	}, tigrisheaders.WithSnapshotVersion(snap.SnapshotVersion))
+	if err != nil {
+		t.Fatalf("GetObject() at snapshot failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if data, _ := io.ReadAll(resp.Body); string(data) != "v1" {
+		t.Errorf("GetObject() at snapshot = %q, want %q", data, "v1")
+	}
+}
+
+func TestClient_ListBucketSnapshots(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateSnapshotEnabledBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateSnapshotEnabledBucket() failed: %v", err)
+	}
+	for _, desc := range []string{"first", "second"} {
+		if _, err := client.CreateBucketSnapshot(ctx, desc, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+			t.Fatalf("CreateBucketSnapshot(%q) failed: %v", desc, err)
+		}
+	}
+
+	out, err := client.ListBucketSnapshots(ctx, "bucket")
+	if err != nil {
+		t.Fatalf("ListBucketSnapshots() failed: %v", err)
+	}
+	if len(out.Buckets) != 2 {
+		t.Fatalf("ListBucketSnapshots() returned %d entries, want 2", len(out.Buckets))
+	}
+}
+
+func TestParseSnapshotName(t *testing.T) {
+	tests := []struct {
+		name        string
+		entry       string
+		wantVersion string
+		wantDesc    string
+		wantErr     bool
+	}{
+		{
+			name:        "version only",
+			entry:       "1751631910169675092",
+			wantVersion: "1751631910169675092",
+		},
+		{
+			name:        "with description",
+			entry:       "1751631910169675092; name=nightly",
+			wantVersion: "1751631910169675092",
+			wantDesc:    "nightly",
+		},
+		{
+			name:        "escaped description",
+			entry:       "1751631910169675092; name=" + url.QueryEscape("before v2; a=b & c"),
+			wantVersion: "1751631910169675092",
+			wantDesc:    "before v2; a=b & c",
+		},
+		{
+			name:        "unknown parameters are ignored",
+			entry:       "42;foo=bar; name=x",
+			wantVersion: "42",
+			wantDesc:    "x",
+		},
+		{
+			name:    "empty entry",
+			entry:   "",
+			wantErr: true,
+		},
+		{
+			name:    "missing version",
+			entry:   "; name=x",
+			wantErr: true,
+		},
+		{
+			name:    "bad escape",
+			entry:   "42; name=%zz",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, desc, err := ParseSnapshotName(tt.entry)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseSnapshotName(%q) succeeded, want error", tt.entry)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSnapshotName(%q) failed: %v", tt.entry, err)
+			}
+			if version != tt.wantVersion || desc != tt.wantDesc {
+				t.Errorf("ParseSnapshotName(%q) = %q, %q, want %q, %q", tt.entry, version, desc, tt.wantVersion, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestClient_RenameObject(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("old-name.txt"),
+		Body:   strings.NewReader("data"),
+	}, tigrisheaders.WithHeader("X-Amz-Meta-Kept", "yes")); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+
+	if _, err := client.RenameObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String("bucket"),
+		CopySource: aws.String("bucket/old-name.txt"),
+		Key:        aws.String("new-name.txt"),
+	}); err != nil {
+		t.Fatalf("RenameObject() failed: %v", err)
+	}
+
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("new-name.txt")})
+	if err != nil {
+		t.Fatalf("HeadObject(new-name.txt) failed: %v", err)
+	}
+	if head.Metadata["kept"] != "yes" {
+		t.Errorf("renamed object metadata = %v, want kept=yes", head.Metadata)
+	}
+
+	if _, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("old-name.txt")}); err == nil {
+		t.Error("HeadObject(old-name.txt) succeeded after rename, want not found")
+	}
+}
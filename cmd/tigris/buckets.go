@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tigrisdata/storage-go/simplestorage"
+)
+
+// mb creates a bucket, optionally with snapshots enabled.
+func (a *app) mb(ctx context.Context, args []string) error {
+	fs := a.flags("mb")
+	snapshots := fs.Bool("snapshots", false, "enable snapshots and forks for the bucket")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	bucket, err := parseBucket(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	var opts []simplestorage.BucketOption
+	if *snapshots {
+		opts = append(opts, simplestorage.WithEnableSnapshot())
+	}
+
+	_, err = cli.CreateBucket(ctx, bucket, opts...)
+	return err
+}
+
+// rb deletes a bucket, optionally emptying it first.
+func (a *app) rb(ctx context.Context, args []string) error {
+	fs := a.flags("rb")
+	force := fs.Bool("force", false, "delete every object in the bucket first")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	bucket, err := parseBucket(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	var opts []simplestorage.BucketOption
+	if *force {
+		opts = append(opts, simplestorage.WithForce())
+	}
+
+	return cli.DeleteBucket(ctx, bucket, opts...)
+}
+
+// snapshot runs the snapshot create and snapshot list subcommands.
+func (a *app) snapshot(ctx context.Context, args []string) error {
+	fs := a.flags("snapshot")
+	if err := parse(fs, args, 2, 3); err != nil {
+		return err
+	}
+
+	bucket, err := parseBucket(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "create":
+		snap, err := cli.CreateBucketSnapshot(ctx, bucket, fs.Arg(2))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(a.stdout, snap.Version)
+		return nil
+
+	case "list":
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return errUsage
+		}
+
+		tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+
+		var listOpts []simplestorage.BucketOption
+		for {
+			list, err := cli.ListBucketSnapshots(ctx, bucket, listOpts...)
+			if err != nil {
+				return err
+			}
+
+			for _, snap := range list.Snapshots {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", snap.Version, snap.Created.Format(time.DateTime), snap.Name)
+			}
+
+			if !list.Truncated {
+				break
+			}
+			listOpts = []simplestorage.BucketOption{simplestorage.WithListToken(list.NextToken)}
+		}
+
+		return tw.Flush()
+	}
+
+	fs.Usage()
+	return errUsage
+}
+
+// fork creates a fork of a bucket, optionally from a snapshot.
+func (a *app) fork(ctx context.Context, args []string) error {
+	fs := a.flags("fork")
+	snapshot := fs.String("snapshot", "", "fork from the snapshot `version` instead of the current contents")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	source, err := parseBucket(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	target, err := parseBucket(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	var opts []simplestorage.BucketOption
+	if *snapshot != "" {
+		opts = append(opts, simplestorage.WithSnapshotVersion(*snapshot))
+	}
+
+	_, err = cli.ForkBucket(ctx, source, target, opts...)
+	return err
+}
+
+// info prints the fork and snapshot metadata of a bucket.
+func (a *app) info(ctx context.Context, args []string) error {
+	fs := a.flags("info")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	bucket, err := parseBucket(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.storageClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	info, err := cli.HeadBucketForkOrSnapshot(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 1, ' ', 0)
+	fmt.Fprintf(tw, "Bucket:\t%s\n", bucket)
+	fmt.Fprintf(tw, "Snapshots enabled:\t%t\n", info.SnapshotsEnabled)
+	fmt.Fprintf(tw, "Fork parent:\t%t\n", info.IsForkParent)
+	fmt.Fprintf(tw, "Source bucket:\t%s\n", info.SourceBucket)
+	fmt.Fprintf(tw, "Source snapshot:\t%s\n", info.SourceBucketSnapshot)
+	return tw.Flush()
+}
+
+// parseBucket parses a bucket argument, which may have a tigris:// prefix but no key.
+func parseBucket(arg string) (string, error) {
+	bucket, key, err := parsePath(arg)
+	if err != nil {
+		return "", err
+	}
+	if key != "" {
+		return "", fmt.Errorf("%q: expected a bucket name, not an object", arg)
+	}
+	return bucket, nil
+}
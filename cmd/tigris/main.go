@@ -0,0 +1,213 @@
+// Command tigris is a small command-line client for Tigris built on this module.
+//
+// Usage:
+//
+//	tigris [flags] <command> [arguments]
+//
+// The commands are:
+//
+//	ls [bucket[/prefix]]               list buckets, or the objects in a bucket
+//	cat bucket/key...                  print objects to standard output
+//	cp src dst                         copy between local files and Tigris
+//	mv bucket/key bucket/key           rename an object in place
+//	rm [-r] bucket/key...              delete objects, or everything under a prefix
+//	presign [-method m] bucket/key     print a presigned URL for an object
+//	mb [-snapshots] bucket             create a bucket
+//	rb [-force] bucket                 delete a bucket
+//	snapshot create bucket [desc]      take a snapshot of a bucket
+//	snapshot list bucket               list the snapshots of a bucket
+//	fork [-snapshot v] source target   fork a bucket
+//	info bucket                        print the fork and snapshot metadata of a bucket
+//
+// Objects are named bucket/key, optionally with a tigris:// prefix. In cp, remote
+// paths must use the tigris:// prefix, and "-" stands for standard input or output.
+//
+// Credentials are read from the same environment variables as storage.New:
+// TIGRIS_STORAGE_ACCESS_KEY_ID and TIGRIS_STORAGE_SECRET_ACCESS_KEY. If they are not
+// set, the AWS configuration resolution method is used.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/simplestorage"
+)
+
+// errUsage is returned by commands called with bad arguments, after the usage has
+// been printed.
+var errUsage = errors.New("usage error")
+
+// remotePrefix marks remote paths in cp.
+const remotePrefix = "tigris://"
+
+// app holds the global configuration and I/O streams shared by all commands.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	endpoint  string
+	region    string
+	pathStyle bool
+}
+
+// command is a tigris subcommand.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(a *app, ctx context.Context, args []string) error
+}
+
+// commands lists the subcommands in the order they are shown in the usage.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"ls", "[bucket[/prefix]]", "list buckets, or the objects in a bucket", (*app).ls},
+		{"cat", "bucket/key...", "print objects to standard output", (*app).cat},
+		{"cp", "src dst", "copy between local files and Tigris", (*app).cp},
+		{"mv", "bucket/key bucket/key", "rename an object in place", (*app).mv},
+		{"rm", "[-r] bucket/key...", "delete objects, or everything under a prefix", (*app).rm},
+		{"presign", "[-method m] [-expiry d] bucket/key", "print a presigned URL for an object", (*app).presign},
+		{"mb", "[-snapshots] bucket", "create a bucket", (*app).mb},
+		{"rb", "[-force] bucket", "delete a bucket", (*app).rb},
+		{"snapshot", "create|list bucket", "take or list snapshots of a bucket", (*app).snapshot},
+		{"fork", "[-snapshot v] source target", "fork a bucket", (*app).fork},
+		{"info", "bucket", "print the fork and snapshot metadata of a bucket", (*app).info},
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "tigris: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags in args and runs the named command.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	a := &app{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	fs := flag.NewFlagSet("tigris", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&a.endpoint, "endpoint", storage.GlobalEndpoint, "Tigris `URL` to connect to")
+	fs.StringVar(&a.region, "region", "auto", "S3 `region` to sign requests for")
+	fs.BoolVar(&a.pathStyle, "path-style", false, "use path-style addressing")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: tigris [flags] <command> [arguments]\n\nCommands:\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(stderr, "  %-9s %-36s %s\n", cmd.name, cmd.args, cmd.summary)
+		}
+		fmt.Fprintf(stderr, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	name := fs.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(a, ctx, fs.Args()[1:])
+		}
+	}
+
+	fmt.Fprintf(stderr, "tigris: unknown command %q\n", name)
+	fs.Usage()
+	return errUsage
+}
+
+// flags returns a flag set for the named command that prints its usage to stderr.
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("tigris "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		for _, cmd := range commands {
+			if cmd.name == name {
+				fmt.Fprintf(a.stderr, "Usage: tigris %s %s\n\n%s.\n", cmd.name, cmd.args, cmd.summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args with fs and checks that between min and max positional arguments
+// are left, printing the usage if not. A negative max means no limit.
+func parse(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < min || (max >= 0 && fs.NArg() > max) {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// storageClient returns a storage.Client for the configured endpoint.
+func (a *app) storageClient(ctx context.Context) (*storage.Client, error) {
+	return storage.New(ctx,
+		storage.WithEndpoint(a.endpoint),
+		storage.WithRegion(a.region),
+		storage.WithPathStyle(a.pathStyle),
+	)
+}
+
+// client returns a simplestorage.Client for bucket on the configured endpoint.
+func (a *app) client(ctx context.Context, bucket string) (*simplestorage.Client, error) {
+	return simplestorage.New(ctx,
+		simplestorage.WithBucket(bucket),
+		simplestorage.WithEndpoint(a.endpoint),
+		simplestorage.WithRegion(a.region),
+		simplestorage.WithPathStyle(a.pathStyle),
+	)
+}
+
+// parsePath splits a bucket/key argument, with an optional tigris:// prefix, into the
+// bucket and key. The key may be empty.
+func parsePath(arg string) (bucket, key string, err error) {
+	bucket, key, _ = strings.Cut(strings.TrimPrefix(arg, remotePrefix), "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("%q: missing bucket name", arg)
+	}
+	return bucket, key, nil
+}
+
+// parseObject is like parsePath, but also requires a key.
+func parseObject(arg string) (bucket, key string, err error) {
+	bucket, key, err = parsePath(arg)
+	if err != nil {
+		return "", "", err
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("%q: missing object key", arg)
+	}
+	return bucket, key, nil
+}
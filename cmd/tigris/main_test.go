@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tigrisdata/storage-go/tigristest"
+)
+
+// newTestServer starts a fake Tigris server and sets the credentials environment
+// variables for the duration of the test.
+func newTestServer(t *testing.T) *tigristest.Server {
+	t.Helper()
+
+	t.Setenv("TIGRIS_STORAGE_ACCESS_KEY_ID", "test")
+	t.Setenv("TIGRIS_STORAGE_SECRET_ACCESS_KEY", "test")
+
+	srv := tigristest.NewServer()
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// tigris runs the command line args against srv with stdin as standard input and
+// returns what it wrote to standard output.
+func tigris(t *testing.T, srv *tigristest.Server, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-endpoint", srv.URL, "-path-style"}, args...)
+
+	err := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	if err != nil {
+		t.Logf("tigris %s: stderr:\n%s", strings.Join(args, " "), stderr.String())
+	}
+
+	return stdout.String(), err
+}
+
+// mustTigris is like tigris, but fails the test if the command fails.
+func mustTigris(t *testing.T, srv *tigristest.Server, stdin string, args ...string) string {
+	t.Helper()
+
+	out, err := tigris(t, srv, stdin, args...)
+	if err != nil {
+		t.Fatalf("tigris %s: %v", strings.Join(args, " "), err)
+	}
+
+	return out
+}
+
+func TestObjects(t *testing.T) {
+	srv := newTestServer(t)
+	dir := t.TempDir()
+
+	mustTigris(t, srv, "", "mb", "files")
+
+	local := filepath.Join(dir, "hello.txt")
+	if err := os.WriteFile(local, []byte("hello, world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mustTigris(t, srv, "", "cp", local, "tigris://files/docs/")
+	mustTigris(t, srv, "from stdin", "cp", "-", "tigris://files/stdin.txt")
+
+	if got := mustTigris(t, srv, "", "cat", "files/docs/hello.txt", "tigris://files/stdin.txt"); got != "hello, worldfrom stdin" {
+		t.Errorf("cat = %q, want %q", got, "hello, worldfrom stdin")
+	}
+
+	mustTigris(t, srv, "", "cp", "tigris://files/docs/hello.txt", "tigris://files/backup/")
+	mustTigris(t, srv, "", "mv", "files/stdin.txt", "files/docs/stdin.txt")
+
+	out := mustTigris(t, srv, "", "ls", "files/docs/")
+	for _, want := range []string{"docs/hello.txt", "docs/stdin.txt"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ls output is missing %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "backup/") {
+		t.Errorf("ls output has objects outside the prefix:\n%s", out)
+	}
+
+	downloaded := filepath.Join(dir, "out")
+	if err := os.Mkdir(downloaded, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	mustTigris(t, srv, "", "cp", "tigris://files/backup/hello.txt", downloaded)
+
+	data, err := os.ReadFile(filepath.Join(downloaded, "hello.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello, world" {
+		t.Errorf("downloaded %q, want %q", data, "hello, world")
+	}
+
+	if got := mustTigris(t, srv, "", "cp", "tigris://files/docs/stdin.txt", "-"); got != "from stdin" {
+		t.Errorf("cp to stdout = %q, want %q", got, "from stdin")
+	}
+
+	u := mustTigris(t, srv, "", "presign", "-method", "put", "files/docs/hello.txt")
+	if !strings.Contains(u, "X-Amz-Signature=") {
+		t.Errorf("presign = %q, want a presigned URL", u)
+	}
+
+	mustTigris(t, srv, "", "rm", "files/backup/hello.txt")
+	if got := mustTigris(t, srv, "", "rm", "-r", "files/docs/"); got != "delete files/docs/hello.txt\ndelete files/docs/stdin.txt\n" {
+		t.Errorf("rm -r output = %q", got)
+	}
+
+	if got := mustTigris(t, srv, "", "ls", "files"); got != "" {
+		t.Errorf("ls after rm = %q, want nothing", got)
+	}
+
+	mustTigris(t, srv, "", "rb", "files")
+
+	if got := mustTigris(t, srv, "", "ls"); strings.Contains(got, "files") {
+		t.Errorf("ls after rb = %q, want no files bucket", got)
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	srv := newTestServer(t)
+
+	mustTigris(t, srv, "", "mb", "-snapshots", "source")
+	mustTigris(t, srv, "v1", "cp", "-", "tigris://source/data.txt")
+
+	version := strings.TrimSpace(mustTigris(t, srv, "", "snapshot", "create", "source", "before change"))
+	if version == "" {
+		t.Fatal("snapshot create printed no version")
+	}
+
+	mustTigris(t, srv, "v2", "cp", "-", "tigris://source/data.txt")
+
+	if got := mustTigris(t, srv, "", "snapshot", "list", "source"); !strings.Contains(got, version) || !strings.Contains(got, "before change") {
+		t.Errorf("snapshot list = %q, want version %s named %q", got, version, "before change")
+	}
+
+	if got := mustTigris(t, srv, "", "cat", "-snapshot", version, "source/data.txt"); got != "v1" {
+		t.Errorf("cat -snapshot = %q, want %q", got, "v1")
+	}
+
+	mustTigris(t, srv, "", "fork", "-snapshot", version, "source", "forked")
+
+	if got := mustTigris(t, srv, "", "cat", "forked/data.txt"); got != "v1" {
+		t.Errorf("cat of fork = %q, want %q", got, "v1")
+	}
+
+	// Collapse the column padding so the checks don't depend on the alignment.
+	info := strings.Join(strings.Fields(mustTigris(t, srv, "", "info", "forked")), " ")
+	for _, want := range []string{"Source bucket: source", "Source snapshot: " + version} {
+		if !strings.Contains(info, want) {
+			t.Errorf("info output is missing %q:\n%s", want, info)
+		}
+	}
+
+	info = strings.Join(strings.Fields(mustTigris(t, srv, "", "info", "source")), " ")
+	for _, want := range []string{"Snapshots enabled: true", "Fork parent: true"} {
+		if !strings.Contains(info, want) {
+			t.Errorf("info output is missing %q:\n%s", want, info)
+		}
+	}
+
+	mustTigris(t, srv, "", "rb", "-force", "forked")
+}
+
+func TestUsage(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, tt := range []struct {
+		name string
+		args []string
+		want error
+	}{
+		{"no command", nil, errUsage},
+		{"unknown command", []string{"frobnicate"}, errUsage},
+		{"missing argument", []string{"cat"}, errUsage},
+		{"extra argument", []string{"info", "a", "b"}, errUsage},
+		{"bad snapshot subcommand", []string{"snapshot", "delete", "bucket"}, errUsage},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tigris(t, srv, "", tt.args...); !errors.Is(err, tt.want) {
+				t.Errorf("run() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name string
+		args []string
+	}{
+		{"both local", []string{"cp", "a", "b"}},
+		{"missing key", []string{"cat", "bucket"}},
+		{"missing bucket", []string{"cat", "/key"}},
+		{"rename across buckets", []string{"mv", "a/key", "b/key"}},
+		{"rm without key", []string{"rm", "bucket"}},
+		{"object for bucket", []string{"mb", "bucket/key"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tigris(t, srv, "", tt.args...); err == nil || errors.Is(err, errUsage) {
+				t.Errorf("run() = %v, want an argument error", err)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	for _, tt := range []struct {
+		arg         string
+		bucket, key string
+		wantErr     bool
+	}{
+		{arg: "bucket", bucket: "bucket"},
+		{arg: "bucket/", bucket: "bucket"},
+		{arg: "bucket/dir/key.txt", bucket: "bucket", key: "dir/key.txt"},
+		{arg: "tigris://bucket/key", bucket: "bucket", key: "key"},
+		{arg: "", wantErr: true},
+		{arg: "tigris:///key", wantErr: true},
+	} {
+		t.Run(tt.arg, func(t *testing.T) {
+			bucket, key, err := parsePath(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePath(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if bucket != tt.bucket || key != tt.key {
+				t.Errorf("parsePath(%q) = %q, %q, want %q, %q", tt.arg, bucket, key, tt.bucket, tt.key)
+			}
+		})
+	}
+}
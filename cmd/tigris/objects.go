@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tigrisdata/storage-go/simplestorage"
+)
+
+// ls lists the buckets, or the objects in a bucket under an optional prefix.
+func (a *app) ls(ctx context.Context, args []string) error {
+	fs := a.flags("ls")
+	snapshot := fs.String("snapshot", "", "list objects as of the snapshot `version`")
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+
+	if fs.NArg() == 0 {
+		cli, err := a.storageClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		pages := s3.NewListBucketsPaginator(cli, &s3.ListBucketsInput{})
+		for pages.HasMorePages() {
+			page, err := pages.NextPage(ctx)
+			if err != nil {
+				return err
+			}
+			for _, b := range page.Buckets {
+				fmt.Fprintf(tw, "%s\t%s\n", aws.ToTime(b.CreationDate).Format(time.DateTime), aws.ToString(b.Name))
+			}
+		}
+
+		return tw.Flush()
+	}
+
+	bucket, prefix, err := parsePath(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	opts := []simplestorage.ClientOption{simplestorage.WithPrefix(prefix)}
+	if *snapshot != "" {
+		opts = append(opts, simplestorage.WithSnapshot(*snapshot))
+	}
+
+	for obj, err := range cli.ListAll(ctx, opts...) {
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", obj.LastModified.Format(time.DateTime), obj.Size, obj.Key)
+	}
+
+	return tw.Flush()
+}
+
+// cat writes the contents of objects to standard output.
+func (a *app) cat(ctx context.Context, args []string) error {
+	fs := a.flags("cat")
+	snapshot := fs.String("snapshot", "", "read objects as of the snapshot `version`")
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	var opts []simplestorage.ClientOption
+	if *snapshot != "" {
+		opts = append(opts, simplestorage.WithSnapshot(*snapshot))
+	}
+
+	for _, arg := range fs.Args() {
+		bucket, key, err := parseObject(arg)
+		if err != nil {
+			return err
+		}
+
+		cli, err := a.client(ctx, bucket)
+		if err != nil {
+			return err
+		}
+
+		obj, err := cli.Get(ctx, key, opts...)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(a.stdout, obj.Body)
+		obj.Body.Close()
+		if err != nil {
+			return fmt.Errorf("can't read %s/%s: %w", bucket, key, err)
+		}
+	}
+
+	return nil
+}
+
+// cp copies an object between a local file and Tigris, or between two objects.
+func (a *app) cp(ctx context.Context, args []string) error {
+	fs := a.flags("cp")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	src, dst := fs.Arg(0), fs.Arg(1)
+	srcRemote, dstRemote := strings.HasPrefix(src, remotePrefix), strings.HasPrefix(dst, remotePrefix)
+
+	switch {
+	case srcRemote && dstRemote:
+		return a.copyObject(ctx, src, dst)
+	case dstRemote:
+		return a.upload(ctx, src, dst)
+	case srcRemote:
+		return a.download(ctx, src, dst)
+	default:
+		return fmt.Errorf("cp: one of %q and %q must be a %s path", src, dst, remotePrefix)
+	}
+}
+
+// copyObject copies the object at src to dst with a server-side copy.
+func (a *app) copyObject(ctx context.Context, src, dst string) error {
+	srcBucket, srcKey, err := parseObject(src)
+	if err != nil {
+		return err
+	}
+
+	dstBucket, dstKey, err := parsePath(dst)
+	if err != nil {
+		return err
+	}
+	if dstKey == "" || strings.HasSuffix(dstKey, "/") {
+		dstKey += path.Base(srcKey)
+	}
+
+	cli, err := a.client(ctx, dstBucket)
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.Copy(ctx, srcKey, dstKey, simplestorage.WithSourceBucket(srcBucket))
+	return err
+}
+
+// upload puts the local file src, or standard input if src is "-", at dst.
+func (a *app) upload(ctx context.Context, src, dst string) error {
+	bucket, key, err := parsePath(dst)
+	if err != nil {
+		return err
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		if src == "-" {
+			return fmt.Errorf("cp: %q: missing object key for standard input", dst)
+		}
+		key += filepath.Base(src)
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	obj := &simplestorage.Object{
+		Key:  key,
+		Body: io.NopCloser(a.stdin),
+	}
+
+	if src != "-" {
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		st, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		obj.Body = f
+		obj.Size = st.Size()
+		obj.ContentType = mime.TypeByExtension(filepath.Ext(src))
+	}
+
+	_, err = cli.Put(ctx, obj)
+	return err
+}
+
+// download writes the object at src to the local file dst, or to standard output
+// if dst is "-".
+func (a *app) download(ctx context.Context, src, dst string) error {
+	bucket, key, err := parseObject(src)
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	if dst == "-" {
+		obj, err := cli.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		defer obj.Body.Close()
+
+		if _, err := io.Copy(a.stdout, obj.Body); err != nil {
+			return fmt.Errorf("can't read %s/%s: %w", bucket, key, err)
+		}
+		return nil
+	}
+
+	if st, err := os.Stat(dst); (err == nil && st.IsDir()) || strings.HasSuffix(dst, string(filepath.Separator)) {
+		dst = filepath.Join(dst, path.Base(key))
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cli.Download(ctx, key, f); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	return f.Close()
+}
+
+// mv renames an object within its bucket without copying its data.
+func (a *app) mv(ctx context.Context, args []string) error {
+	fs := a.flags("mv")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	bucket, src, err := parseObject(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	dstBucket, dst, err := parseObject(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	if dstBucket != bucket {
+		return fmt.Errorf("mv: can't rename across buckets (%s to %s), use cp and rm instead", bucket, dstBucket)
+	}
+
+	cli, err := a.storageClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.RenameObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(bucket),
+		CopySource: aws.String(bucket + "/" + (&url.URL{Path: src}).EscapedPath()),
+		Key:        aws.String(dst),
+	})
+	return err
+}
+
+// rm deletes objects, or with -r, every object under each prefix.
+func (a *app) rm(ctx context.Context, args []string) error {
+	fs := a.flags("rm")
+	recursive := fs.Bool("r", false, "delete every object under each prefix")
+	dryRun := fs.Bool("n", false, "print what would be deleted without deleting it")
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	// Keys are deleted in one batch per bucket, in the order the buckets first appear.
+	var (
+		buckets []string
+		keys    = map[string][]string{}
+	)
+
+	for _, arg := range fs.Args() {
+		bucket, key, err := parsePath(arg)
+		if err != nil {
+			return err
+		}
+		if key == "" && !*recursive {
+			return fmt.Errorf("rm: %q: missing object key, use -r to delete everything in the bucket", arg)
+		}
+
+		if _, ok := keys[bucket]; !ok {
+			buckets = append(buckets, bucket)
+		}
+		keys[bucket] = append(keys[bucket], key)
+	}
+
+	var opts []simplestorage.ClientOption
+	if *dryRun {
+		opts = append(opts, simplestorage.WithDryRun())
+	}
+
+	var errs []error
+
+	for _, bucket := range buckets {
+		cli, err := a.client(ctx, bucket)
+		if err != nil {
+			return err
+		}
+
+		var reports []*simplestorage.DeleteReport
+		if *recursive {
+			for _, prefix := range keys[bucket] {
+				report, err := cli.DeletePrefix(ctx, prefix, opts...)
+				reports = append(reports, report)
+				errs = append(errs, err)
+			}
+		} else {
+			report, err := cli.DeleteMany(ctx, keys[bucket], opts...)
+			reports = append(reports, report)
+			errs = append(errs, err)
+		}
+
+		for _, report := range reports {
+			if report == nil {
+				continue
+			}
+			for _, key := range report.Deleted {
+				fmt.Fprintf(a.stdout, "delete %s/%s\n", bucket, key)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// presign prints a presigned URL for an object.
+func (a *app) presign(ctx context.Context, args []string) error {
+	fs := a.flags("presign")
+	method := fs.String("method", http.MethodGet, "HTTP `method` the URL is for: GET, HEAD, PUT or DELETE")
+	expiry := fs.Duration("expiry", time.Hour, "how long the URL stays valid")
+	contentType := fs.String("content-type", "", "Content-Type the uploader must send, for PUT")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	bucket, key, err := parseObject(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli, err := a.client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	var opts []simplestorage.ClientOption
+	if *contentType != "" {
+		opts = append(opts, simplestorage.WithContentType(*contentType))
+	}
+
+	u, err := cli.PresignURL(ctx, strings.ToUpper(*method), key, *expiry, opts...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, u)
+	return nil
+}
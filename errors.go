@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Sentinel errors for common Tigris failures. Errors returned by Client calls can be
+// checked against them with errors.Is:
+//
+//	_, err := client.GetObject(ctx, in)
+//	if errors.Is(err, storage.ErrObjectNotFound) {
+//		// ...
+//	}
+var (
+	// ErrObjectNotFound means the requested object does not exist.
+	ErrObjectNotFound = errors.New("storage: object not found")
+
+	// ErrBucketNotFound means the requested bucket does not exist.
+	ErrBucketNotFound = errors.New("storage: bucket not found")
+
+	// ErrBucketNotEmpty means a bucket can't be deleted because it still has objects.
+	ErrBucketNotEmpty = errors.New("storage: bucket not empty")
+
+	// ErrBucketAlreadyExists means a bucket with the requested name already exists.
+	ErrBucketAlreadyExists = errors.New("storage: bucket already exists")
+
+	// ErrPreconditionFailed means a conditional request, such as one using If-Match,
+	// did not hold.
+	ErrPreconditionFailed = errors.New("storage: precondition failed")
+
+	// ErrNotModified means a conditional read, such as one using If-None-Match, found
+	// the object unchanged.
+	ErrNotModified = errors.New("storage: not modified")
+
+	// ErrAccessDenied means the credentials are not allowed to perform the request.
+	ErrAccessDenied = errors.New("storage: access denied")
+
+	// ErrSnapshotsNotEnabled means a snapshot or fork was requested for a bucket that
+	// was not created with snapshots enabled.
+	ErrSnapshotsNotEnabled = errors.New("storage: snapshots not enabled for bucket")
+
+	// ErrSlowDown means Tigris is throttling requests and the caller should back off.
+	ErrSlowDown = errors.New("storage: slow down")
+)
+
+// Error is an error response from Tigris. It matches the sentinel error for its code
+// with errors.Is, and unwraps to the underlying AWS SDK error so errors.As still finds
+// smithy.APIError and friends.
+type Error struct {
+	Operation  string // Name of the S3 operation, such as GetObject
+	Code       string // S3 error code, such as NoSuchKey
+	Message    string // Human-readable message from Tigris
+	StatusCode int    // HTTP status code of the response
+	RequestID  string // Tigris request ID, for support requests
+	HostID     string // Tigris host ID, for support requests
+
+	kind error
+	err  error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("storage: %s: api error %s", e.Operation, e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return fmt.Sprintf("%s (status %d, request ID %q, host ID %q)", msg, e.StatusCode, e.RequestID, e.HostID)
+}
+
+// Is reports whether target is the sentinel error for e's code.
+func (e *Error) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
+// Unwrap returns the underlying AWS SDK error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// WrapError converts an error returned by the AWS SDK for a Tigris call into an
+// *Error. Errors that don't carry a Tigris response, such as network failures, are
+// returned unchanged. Clients created with New already do this for every call.
+func WrapError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var already *Error
+	if errors.As(err, &already) {
+		return err
+	}
+
+	e := &Error{
+		Operation: operation,
+		err:       err,
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		e.Code = apiErr.ErrorCode()
+		e.Message = apiErr.ErrorMessage()
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		e.StatusCode = respErr.HTTPStatusCode()
+		e.RequestID = respErr.ServiceRequestID()
+	}
+
+	var hostErr interface{ ServiceHostID() string }
+	if errors.As(err, &hostErr) {
+		e.HostID = hostErr.ServiceHostID()
+	}
+
+	if e.Code == "" && e.StatusCode == 0 {
+		return err
+	}
+
+	e.kind = errorKind(operation, e.Code, e.StatusCode)
+	if e.Code == "" {
+		e.Code = http.StatusText(e.StatusCode)
+	}
+
+	return e
+}
+
+// errorKind maps an S3 error code and HTTP status to a sentinel error. Responses to
+// HEAD requests have no body, so they only carry the status code.
+func errorKind(operation, code string, status int) error {
+	switch code {
+	case "NoSuchKey":
+		return ErrObjectNotFound
+	case "NoSuchBucket":
+		return ErrBucketNotFound
+	case "BucketNotEmpty":
+		return ErrBucketNotEmpty
+	case "BucketAlreadyExists", "BucketAlreadyOwnedByYou":
+		return ErrBucketAlreadyExists
+	case "PreconditionFailed":
+		return ErrPreconditionFailed
+	case "NotModified":
+		return ErrNotModified
+	case "AccessDenied":
+		return ErrAccessDenied
+	case "SnapshotsNotEnabled":
+		return ErrSnapshotsNotEnabled
+	case "SlowDown", "TooManyRequests":
+		return ErrSlowDown
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		if operation == "HeadBucket" {
+			return ErrBucketNotFound
+		}
+		return ErrObjectNotFound
+	case http.StatusNotModified:
+		return ErrNotModified
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusForbidden:
+		return ErrAccessDenied
+	case http.StatusTooManyRequests:
+		return ErrSlowDown
+	}
+
+	return nil
+}
+
+// addErrorMiddleware installs a middleware that converts the errors of every call
+// into *Error.
+func addErrorMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TigrisError", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, md, err := next.HandleInitialize(ctx, in)
+		if err != nil {
+			err = WrapError(awsmiddleware.GetOperationName(ctx), err)
+		}
+		return out, md, err
+	}), middleware.After)
+}
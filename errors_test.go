@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("exists"),
+		Body:   strings.NewReader("data"),
+	}); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		want     error
+		wantCode string
+	}{
+		{
+			name: "get missing object",
+			call: func() error {
+				_, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("missing")})
+				return err
+			},
+			want:     ErrObjectNotFound,
+			wantCode: "NoSuchKey",
+		},
+		{
+			name: "head missing object",
+			call: func() error {
+				_, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("missing")})
+				return err
+			},
+			want:     ErrObjectNotFound,
+			wantCode: "NotFound",
+		},
+		{
+			name: "head missing bucket",
+			call: func() error {
+				_, err := client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String("missing")})
+				return err
+			},
+			want:     ErrBucketNotFound,
+			wantCode: "NotFound",
+		},
+		{
+			name: "list missing bucket",
+			call: func() error {
+				_, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String("missing")})
+				return err
+			},
+			want:     ErrBucketNotFound,
+			wantCode: "NoSuchBucket",
+		},
+		{
+			name: "create existing bucket",
+			call: func() error {
+				_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")})
+				return err
+			},
+			want:     ErrBucketAlreadyExists,
+			wantCode: "BucketAlreadyOwnedByYou",
+		},
+		{
+			name: "delete non-empty bucket",
+			call: func() error {
+				_, err := client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String("bucket")})
+				return err
+			},
+			want:     ErrBucketNotEmpty,
+			wantCode: "BucketNotEmpty",
+		},
+		{
+			name: "create-if-absent on existing object",
+			call: func() error {
+				_, err := client.PutObject(ctx, &s3.PutObjectInput{
+					Bucket: aws.String("bucket"),
+					Key:    aws.String("exists"),
+					Body:   strings.NewReader("data"),
+				}, tigrisheaders.WithCreateObjectIfNotExists())
+				return err
+			},
+			want:     ErrPreconditionFailed,
+			wantCode: "PreconditionFailed",
+		},
+		{
+			name: "snapshot of bucket without snapshots",
+			call: func() error {
+				_, err := client.CreateBucketSnapshot(ctx, "nope", &s3.CreateBucketInput{Bucket: aws.String("bucket")})
+				return err
+			},
+			want:     ErrSnapshotsNotEnabled,
+			wantCode: "SnapshotsNotEnabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+
+			var tigrisErr *Error
+			if !errors.As(err, &tigrisErr) {
+				t.Fatalf("error %v is not a *storage.Error", err)
+			}
+			if tigrisErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", tigrisErr.Code, tt.wantCode)
+			}
+			if tigrisErr.RequestID == "" || tigrisErr.HostID == "" {
+				t.Errorf("RequestID = %q, HostID = %q, want both set", tigrisErr.RequestID, tigrisErr.HostID)
+			}
+			if tigrisErr.Operation == "" {
+				t.Error("Operation is empty")
+			}
+
+			var apiErr smithy.APIError
+			if !errors.As(err, &apiErr) {
+				t.Error("error does not unwrap to smithy.APIError")
+			}
+		})
+	}
+}
+
+func TestWrapError_passthrough(t *testing.T) {
+	plain := errors.New("connection refused")
+	if got := WrapError("GetObject", plain); got != plain {
+		t.Errorf("WrapError() = %v, want the error unchanged", got)
+	}
+	if WrapError("GetObject", nil) != nil {
+		t.Error("WrapError(nil) != nil")
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		operation string
+		code      string
+		status    int
+		want      error
+	}{
+		{"GetObject", "SlowDown", 503, ErrSlowDown},
+		{"PutObject", "", 429, ErrSlowDown},
+		{"GetObject", "AccessDenied", 403, ErrAccessDenied},
+		{"HeadObject", "", 403, ErrAccessDenied},
+		{"CreateBucket", "BucketAlreadyExists", 409, ErrBucketAlreadyExists},
+		{"GetObject", "", 412, ErrPreconditionFailed},
+		{"GetObject", "", 304, ErrNotModified},
+		{"GetObject", "InternalError", 500, nil},
+	}
+
+	for _, tt := range tests {
+		if got := errorKind(tt.operation, tt.code, tt.status); got != tt.want {
+			t.Errorf("errorKind(%q, %q, %d) = %v, want %v", tt.operation, tt.code, tt.status, got, tt.want)
+		}
+	}
+}
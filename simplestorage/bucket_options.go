@@ -28,6 +28,19 @@ type BucketOptions struct {
 	// ContinuationToken is the pagination token for ListBuckets.
 	ContinuationToken *string
 
+	// Prefix limits ListBuckets to buckets whose names start with it.
+	Prefix *string
+
+	// Force makes DeleteBucket empty the bucket first (see EmptyBucket).
+	Force bool
+
+	// AllowForkParent lets EmptyBucket and forced DeleteBucket calls empty a bucket
+	// that has forks.
+	AllowForkParent bool
+
+	// Progress is called by EmptyBucket after each batch of objects is removed.
+	Progress func(EmptyProgress)
+
 	// S3Options are additional S3 options passed through to the underlying client.
 	S3Options []func(*s3.Options)
 }
@@ -84,3 +97,35 @@ func WithListToken(token string) BucketOption {
 		o.ContinuationToken = &token
 	}
 }
+
+// WithBucketPrefix limits ListBuckets and AllBuckets to buckets whose names start
+// with prefix.
+func WithBucketPrefix(prefix string) BucketOption {
+	return func(o *BucketOptions) {
+		o.Prefix = &prefix
+	}
+}
+
+// WithForce makes DeleteBucket remove everything in the bucket before deleting it,
+// as EmptyBucket does, instead of failing with ErrBucketNotEmpty.
+func WithForce() BucketOption {
+	return func(o *BucketOptions) {
+		o.Force = true
+	}
+}
+
+// WithAllowForkParent lets EmptyBucket and DeleteBucket with WithForce() empty a
+// bucket that other buckets were forked from.
+func WithAllowForkParent() BucketOption {
+	return func(o *BucketOptions) {
+		o.AllowForkParent = true
+	}
+}
+
+// WithProgress sets a function that EmptyBucket and DeleteBucket with WithForce()
+// call with running totals as objects are removed. Calls don't overlap.
+func WithProgress(fn func(EmptyProgress)) BucketOption {
+	return func(o *BucketOptions) {
+		o.Progress = fn
+	}
+}
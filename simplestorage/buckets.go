@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"iter"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	storage "github.com/tigrisdata/storage-go"
 	"github.com/tigrisdata/storage-go/tigrisheaders"
 )
 
@@ -17,13 +19,24 @@ var (
 	ErrBucketNameRequired = errors.New("simplestorage: bucket name required for bucket management operations")
 
 	// ErrBucketNotFound is returned when a bucket operation fails because the bucket doesn't exist.
-	ErrBucketNotFound = errors.New("simplestorage: bucket not found")
+	ErrBucketNotFound = storage.ErrBucketNotFound
 
 	// ErrBucketNotEmpty is returned when trying to delete a non-empty bucket.
-	ErrBucketNotEmpty = errors.New("simplestorage: bucket not empty")
+	ErrBucketNotEmpty = storage.ErrBucketNotEmpty
+
+	// ErrBucketAlreadyExists is returned when creating or forking into a bucket name
+	// that is already taken.
+	ErrBucketAlreadyExists = storage.ErrBucketAlreadyExists
+
+	// ErrSnapshotsNotEnabled is returned when snapshotting or forking a bucket that was
+	// not created with WithEnableSnapshot().
+	ErrSnapshotsNotEnabled = storage.ErrSnapshotsNotEnabled
 
 	// ErrSnapshotRequired is returned when a snapshot version is required but not provided.
 	ErrSnapshotRequired = errors.New("simplestorage: snapshot version required for this operation")
+
+	// ErrSnapshotNotFound is returned when no snapshot has the requested name.
+	ErrSnapshotNotFound = errors.New("simplestorage: snapshot not found")
 )
 
 // BucketInfo contains metadata about a bucket.
@@ -57,6 +70,8 @@ type SnapshotInfo struct {
 type SnapshotList struct {
 	Snapshots []SnapshotInfo // List of snapshots
 	Bucket    string         // Source bucket name
+	NextToken string         // Pagination token for next page
+	Truncated bool           // True if more results available
 }
 
 // CreateBucket creates a new bucket with the given name.
@@ -97,8 +112,8 @@ func (c *Client) CreateBucket(ctx context.Context, bucket string, opts ...Bucket
 
 // DeleteBucket deletes the bucket with the given name.
 //
-// If the bucket is not empty, returns ErrBucketNotEmpty.
-// The bucket must be manually emptied before deletion.
+// If the bucket is not empty, returns ErrBucketNotEmpty, unless WithForce() is used to
+// remove everything in it first (see EmptyBucket).
 func (c *Client) DeleteBucket(ctx context.Context, bucket string, opts ...BucketOption) error {
 	if bucket == "" {
 		return ErrBucketNameRequired
@@ -109,38 +124,29 @@ func (c *Client) DeleteBucket(ctx context.Context, bucket string, opts ...Bucket
 		doer(&o)
 	}
 
+	if o.Force {
+		if _, err := c.emptyBucket(ctx, bucket, o); err != nil {
+			return fmt.Errorf("simplestorage: can't delete bucket %s: %w", bucket, err)
+		}
+	}
+
 	_, err := c.cli.DeleteBucket(ctx, &s3.DeleteBucketInput{
 		Bucket: aws.String(bucket),
 	}, o.S3Options...)
 
 	if err != nil {
-		// Check if the error is because the bucket is not empty
-		// The S3 API error message typically contains "BucketNotEmpty"
-		if containsBucketNotEmptyError(err) {
-			return fmt.Errorf("simplestorage: can't delete bucket %s: %w", bucket, ErrBucketNotEmpty)
-		}
+		// The error matches ErrBucketNotEmpty if the bucket still has objects.
 		return fmt.Errorf("simplestorage: can't delete bucket %s: %w", bucket, err)
 	}
 
 	return nil
 }
 
-// containsBucketNotEmptyError checks if an error indicates a bucket is not empty.
-func containsBucketNotEmptyError(err error) bool {
-	if err == nil {
-		return false
-	}
-	// Check error message for BucketNotEmpty indicator
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "BucketNotEmpty") ||
-		strings.Contains(errMsg, "bucket not empty") ||
-		strings.Contains(errMsg, "NotEmpty")
-}
-
-// ListBuckets lists all buckets that the authenticated user has access to.
+// ListBuckets lists the buckets that the authenticated user has access to.
 //
-// Use WithListToken() for pagination. Note that WithListLimit() is not supported
-// by the underlying S3 ListBuckets API and is ignored.
+// Use WithListLimit() to set the page size, WithListToken() to fetch the next page
+// and WithBucketPrefix() to only list buckets whose names start with a prefix. To
+// walk every page, use AllBuckets.
 func (c *Client) ListBuckets(ctx context.Context, opts ...BucketOption) (*BucketList, error) {
 	o := new(BucketOptions).defaults()
 	for _, doer := range opts {
@@ -149,6 +155,8 @@ func (c *Client) ListBuckets(ctx context.Context, opts ...BucketOption) (*Bucket
 
 	resp, err := c.cli.ListBuckets(ctx, &s3.ListBucketsInput{
 		ContinuationToken: o.ContinuationToken,
+		MaxBuckets:        o.MaxKeys,
+		Prefix:            o.Prefix,
 	}, o.S3Options...)
 
 	if err != nil {
@@ -157,8 +165,9 @@ func (c *Client) ListBuckets(ctx context.Context, opts ...BucketOption) (*Bucket
 
 	result := &BucketList{
 		Buckets:   make([]BucketInfo, 0, len(resp.Buckets)),
-		Truncated: resp.ContinuationToken != nil,
+		NextToken: lower(resp.ContinuationToken, ""),
 	}
+	result.Truncated = result.NextToken != ""
 
 	for _, b := range resp.Buckets {
 		result.Buckets = append(result.Buckets, BucketInfo{
@@ -167,11 +176,45 @@ func (c *Client) ListBuckets(ctx context.Context, opts ...BucketOption) (*Bucket
 		})
 	}
 
-	result.NextToken = lower(resp.ContinuationToken, "")
-
 	return result, nil
 }
 
+// AllBuckets returns an iterator over every bucket that the authenticated user has
+// access to, fetching pages lazily as the loop advances. It accepts the same options
+// as ListBuckets; WithListLimit() sets the page size.
+//
+// If a page can't be fetched or ctx is cancelled, the iterator yields the error once
+// and stops.
+func (c *Client) AllBuckets(ctx context.Context, opts ...BucketOption) iter.Seq2[BucketInfo, error] {
+	return func(yield func(BucketInfo, error) bool) {
+		pageOpts := opts
+
+		for {
+			list, err := c.ListBuckets(ctx, pageOpts...)
+			if err != nil {
+				yield(BucketInfo{}, err)
+				return
+			}
+
+			for _, b := range list.Buckets {
+				if err := ctx.Err(); err != nil {
+					yield(BucketInfo{}, err)
+					return
+				}
+				if !yield(b, nil) {
+					return
+				}
+			}
+
+			if !list.Truncated {
+				return
+			}
+
+			pageOpts = append(slices.Clip(opts), WithListToken(list.NextToken))
+		}
+	}
+}
+
 // GetBucketInfo retrieves metadata about the bucket with the given name.
 //
 // This includes Tigris-specific information like whether snapshots are enabled
@@ -201,6 +244,10 @@ func (c *Client) GetBucketInfo(ctx context.Context, bucket string, opts ...Bucke
 		}, nil
 	}
 
+	if errors.Is(err, ErrBucketNotFound) {
+		return nil, fmt.Errorf("simplestorage: can't get info for bucket %s: %w", bucket, err)
+	}
+
 	// If Tigris-specific metadata is not available, fall back to basic BucketInfo.
 	// This can happen when the bucket doesn't support Tigris features or when
 	// called against non-Tigris S3-compatible storage.
@@ -211,7 +258,9 @@ func (c *Client) GetBucketInfo(ctx context.Context, bucket string, opts ...Bucke
 
 // CreateBucketSnapshot creates a snapshot with the given description for a bucket.
 //
-// The bucket must have snapshots enabled (created with WithEnableSnapshot()).
+// The bucket must have snapshots enabled (created with WithEnableSnapshot()). The
+// returned SnapshotInfo carries the new snapshot's version, which can be passed to
+// WithSnapshotVersion() to fork from the snapshot right away.
 func (c *Client) CreateBucketSnapshot(ctx context.Context, bucket, description string, opts ...BucketOption) (*SnapshotInfo, error) {
 	if bucket == "" {
 		return nil, ErrBucketNameRequired
@@ -223,7 +272,7 @@ func (c *Client) CreateBucketSnapshot(ctx context.Context, bucket, description s
 	}
 
 	// CreateBucketSnapshot uses CreateBucket with snapshot header
-	_, err := c.cli.CreateBucketSnapshot(ctx, description, &s3.CreateBucketInput{
+	resp, err := c.cli.CreateBucketSnapshot(ctx, description, &s3.CreateBucketInput{
 		Bucket: aws.String(bucket),
 	}, o.S3Options...)
 
@@ -231,17 +280,24 @@ func (c *Client) CreateBucketSnapshot(ctx context.Context, bucket, description s
 		return nil, fmt.Errorf("simplestorage: can't create snapshot for bucket %s: %w", bucket, err)
 	}
 
-	// Note: The snapshot version is returned in HTTP headers that are not directly
-	// accessible through the AWS SDK response. Users can list snapshots to get the version.
+	created := resp.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
 	return &SnapshotInfo{
 		Name:    description,
-		Version: "",
-		Created: time.Now(),
+		Version: resp.SnapshotVersion,
+		Created: created,
 		Bucket:  bucket,
 	}, nil
 }
 
-// ListBucketSnapshots lists all snapshots for the given bucket.
+// ListBucketSnapshots lists the snapshots for the given bucket.
+//
+// Each SnapshotInfo carries the snapshot's version, which can be passed to
+// WithSnapshotVersion(), and its description as Name. Use WithListLimit() and
+// WithListToken() to page through buckets with many snapshots.
 func (c *Client) ListBucketSnapshots(ctx context.Context, bucket string, opts ...BucketOption) (*SnapshotList, error) {
 	if bucket == "" {
 		return nil, ErrBucketNameRequired
@@ -255,7 +311,10 @@ func (c *Client) ListBucketSnapshots(ctx context.Context, bucket string, opts ..
 	// Use the new tigrisheaders helper
 	o.S3Options = append(o.S3Options, tigrisheaders.WithListSnapshots(bucket))
 
-	resp, err := c.cli.ListBuckets(ctx, &s3.ListBucketsInput{}, o.S3Options...)
+	resp, err := c.cli.ListBuckets(ctx, &s3.ListBucketsInput{
+		ContinuationToken: o.ContinuationToken,
+		MaxBuckets:        o.MaxKeys,
+	}, o.S3Options...)
 
 	if err != nil {
 		return nil, fmt.Errorf("simplestorage: can't list snapshots for bucket %s: %w", bucket, err)
@@ -263,29 +322,70 @@ func (c *Client) ListBucketSnapshots(ctx context.Context, bucket string, opts ..
 
 	result := &SnapshotList{
 		Bucket:    bucket,
-		Snapshots: make([]SnapshotInfo, 0),
+		Snapshots: make([]SnapshotInfo, 0, len(resp.Buckets)),
+		NextToken: lower(resp.ContinuationToken, ""),
 	}
+	result.Truncated = result.NextToken != ""
 
-	// Parse snapshot info from response buckets
+	// Tigris reports snapshots as buckets named "<version>; name=<description>".
 	for _, b := range resp.Buckets {
-		// Extract snapshot info from bucket metadata.
-		// NOTE: The ListBuckets response does not expose a separate snapshot version field
-		// in the bucket structure, so Version is set to the bucket name. The actual
-		// snapshot version ID is returned in HTTP headers that are not directly
-		// accessible through the AWS SDK response. Use CreateBucketSnapshot for
-		// snapshot creation where the version is returned separately.
-		snap := SnapshotInfo{
-			Name:    lower(b.Name, ""),
-			Version: lower(b.Name, ""),
+		version, name, err := storage.ParseSnapshotName(lower(b.Name, ""))
+		if err != nil {
+			return nil, fmt.Errorf("simplestorage: can't list snapshots for bucket %s: %w", bucket, err)
+		}
+
+		result.Snapshots = append(result.Snapshots, SnapshotInfo{
+			Name:    name,
+			Version: version,
 			Created: lower(b.CreationDate, time.Time{}),
 			Bucket:  bucket,
-		}
-		result.Snapshots = append(result.Snapshots, snap)
+		})
 	}
 
 	return result, nil
 }
 
+// FindSnapshot returns the snapshot of bucket whose description is name, as set
+// with CreateBucketSnapshot. If several snapshots share the description, the most
+// recently created one is returned. If there is no such snapshot, FindSnapshot
+// returns an error wrapping ErrSnapshotNotFound.
+func (c *Client) FindSnapshot(ctx context.Context, bucket, name string, opts ...BucketOption) (*SnapshotInfo, error) {
+	if bucket == "" {
+		return nil, ErrBucketNameRequired
+	}
+
+	var found *SnapshotInfo
+	pageOpts := opts
+
+	for {
+		list, err := c.ListBucketSnapshots(ctx, bucket, pageOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snap := range list.Snapshots {
+			if snap.Name != name {
+				continue
+			}
+			if found == nil || !snap.Created.Before(found.Created) {
+				found = &snap
+			}
+		}
+
+		if !list.Truncated {
+			break
+		}
+
+		pageOpts = append(slices.Clip(opts), WithListToken(list.NextToken))
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("simplestorage: can't find snapshot %q of bucket %s: %w", name, bucket, ErrSnapshotNotFound)
+	}
+
+	return found, nil
+}
+
 // ForkBucket creates a fork of the source bucket with the given target name.
 //
 // Use WithSnapshotVersion() to fork from a specific snapshot version.
@@ -67,6 +67,28 @@ func ExampleClient_DeleteBucket() {
 	}
 }
 
+func ExampleClient_DeleteBucket_force() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Remove every object, version and unfinished upload, then delete the bucket
+	err = client.DeleteBucket(ctx, "my-scratch-bucket",
+		simplestorage.WithForce(),
+		simplestorage.WithProgress(func(p simplestorage.EmptyProgress) {
+			fmt.Printf("removed %d objects, %d versions, %d uploads\n", p.Objects, p.Versions, p.Uploads)
+		}),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+}
+
 func ExampleClient_ListBuckets() {
 	ctx := context.Background()
 
@@ -105,6 +127,29 @@ func ExampleClient_ListBuckets() {
 	}
 }
 
+func ExampleClient_AllBuckets() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Find preview fork buckets without pulling the full list at once
+	for bucket, err := range client.AllBuckets(ctx,
+		simplestorage.WithBucketPrefix("pr-"),
+		simplestorage.WithListLimit(100),
+	) {
+		if err != nil {
+			log.Fatal(err) // handle the error here
+		}
+
+		fmt.Println("Preview bucket:", bucket.Name)
+	}
+}
+
 func ExampleClient_GetBucketInfo() {
 	ctx := context.Background()
 
@@ -167,6 +212,33 @@ func ExampleClient_ListBucketSnapshots() {
 	}
 }
 
+func ExampleClient_FindSnapshot() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Look up a snapshot by the description it was created with
+	snap, err := client.FindSnapshot(ctx, "my-bucket", "Backup before migration")
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	// Fork the bucket as it was when the snapshot was taken
+	fork, err := client.ForkBucket(ctx, "my-bucket", "my-bucket-restore",
+		simplestorage.WithSnapshotVersion(snap.Version),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	fmt.Printf("Restored %s into %s\n", snap.Version, fork.Name)
+}
+
 func ExampleClient_ForkBucket() {
 	ctx := context.Background()
 
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -202,6 +204,121 @@ func TestListBuckets(t *testing.T) {
 	}
 }
 
+func TestListBuckets_pagination(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	// newTestClient already created test-bucket.
+	for _, name := range []string{"pr-1", "pr-2", "pr-3", "prod"} {
+		if _, err := client.CreateBucket(ctx, name); err != nil {
+			t.Fatalf("CreateBucket(%s) failed: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		opts          []BucketOption
+		want          []string
+		wantTruncated bool
+	}{
+		{
+			name: "all buckets",
+			want: []string{"pr-1", "pr-2", "pr-3", "prod", "test-bucket"},
+		},
+		{
+			name:          "limit",
+			opts:          []BucketOption{WithListLimit(2)},
+			want:          []string{"pr-1", "pr-2"},
+			wantTruncated: true,
+		},
+		{
+			name: "prefix",
+			opts: []BucketOption{WithBucketPrefix("pr-")},
+			want: []string{"pr-1", "pr-2", "pr-3"},
+		},
+		{
+			name:          "prefix and limit",
+			opts:          []BucketOption{WithBucketPrefix("pr-"), WithListLimit(2)},
+			want:          []string{"pr-1", "pr-2"},
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := client.ListBuckets(ctx, tt.opts...)
+			if err != nil {
+				t.Fatalf("ListBuckets() failed: %v", err)
+			}
+
+			var got []string
+			for _, b := range list.Buckets {
+				got = append(got, b.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListBuckets() = %v, want %v", got, tt.want)
+			}
+			if list.Truncated != tt.wantTruncated {
+				t.Errorf("ListBuckets() Truncated = %v, want %v", list.Truncated, tt.wantTruncated)
+			}
+			if list.Truncated == (list.NextToken == "") {
+				t.Errorf("ListBuckets() NextToken = %q with Truncated = %v", list.NextToken, list.Truncated)
+			}
+		})
+	}
+
+	t.Run("next page", func(t *testing.T) {
+		first, err := client.ListBuckets(ctx, WithBucketPrefix("pr-"), WithListLimit(2))
+		if err != nil {
+			t.Fatalf("ListBuckets() failed: %v", err)
+		}
+
+		second, err := client.ListBuckets(ctx, WithBucketPrefix("pr-"), WithListLimit(2), WithListToken(first.NextToken))
+		if err != nil {
+			t.Fatalf("ListBuckets() failed: %v", err)
+		}
+		if len(second.Buckets) != 1 || second.Buckets[0].Name != "pr-3" || second.Truncated {
+			t.Errorf("ListBuckets() second page = %+v, want only pr-3", second)
+		}
+	})
+}
+
+func TestAllBuckets(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	for _, name := range []string{"pr-1", "pr-2", "pr-3", "prod"} {
+		if _, err := client.CreateBucket(ctx, name); err != nil {
+			t.Fatalf("CreateBucket(%s) failed: %v", name, err)
+		}
+	}
+
+	var got []string
+	for b, err := range client.AllBuckets(ctx, WithBucketPrefix("pr-"), WithListLimit(1)) {
+		if err != nil {
+			t.Fatalf("AllBuckets() failed: %v", err)
+		}
+		got = append(got, b.Name)
+	}
+	if want := []string{"pr-1", "pr-2", "pr-3"}; !slices.Equal(got, want) {
+		t.Errorf("AllBuckets() = %v, want %v", got, want)
+	}
+
+	got = nil
+	for b, err := range client.AllBuckets(ctx, WithListLimit(2)) {
+		if err != nil {
+			t.Fatalf("AllBuckets() failed: %v", err)
+		}
+		got = append(got, b.Name)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if want := []string{"pr-1", "pr-2", "pr-3"}; !slices.Equal(got, want) {
+		t.Errorf("AllBuckets() with break = %v, want %v", got, want)
+	}
+}
+
 func TestGetBucketInfo(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -423,6 +540,153 @@ func TestForkBucket(t *testing.T) {
 	}
 }
 
+func TestForkBucket_hermetic(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "source", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	fork, err := client.ForkBucket(ctx, "source", "fork")
+	if err != nil {
+		t.Fatalf("ForkBucket() failed: %v", err)
+	}
+	if fork.SourceBucket != "source" {
+		t.Errorf("ForkBucket() SourceBucket = %q, want source", fork.SourceBucket)
+	}
+
+	info, err := client.GetBucketInfo(ctx, "fork")
+	if err != nil {
+		t.Fatalf("GetBucketInfo() failed: %v", err)
+	}
+	if info.SourceBucket != "source" || !info.SnapshotsEnabled {
+		t.Errorf("GetBucketInfo(fork) = %+v, want a snapshot-enabled fork of source", info)
+	}
+
+	parent, err := client.GetBucketInfo(ctx, "source")
+	if err != nil {
+		t.Fatalf("GetBucketInfo() failed: %v", err)
+	}
+	if !parent.IsForkParent {
+		t.Error("GetBucketInfo(source).IsForkParent = false, want true")
+	}
+}
+
+func TestCreateBucketSnapshot_hermetic(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "source", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	src := client.For("source")
+	putTestObject(t, src, "state.txt", []byte("before"))
+
+	snap, err := client.CreateBucketSnapshot(ctx, "source", "before change")
+	if err != nil {
+		t.Fatalf("CreateBucketSnapshot() failed: %v", err)
+	}
+	if snap.Version == "" {
+		t.Fatal("CreateBucketSnapshot() returned no Version")
+	}
+	if snap.Name != "before change" || snap.Bucket != "source" {
+		t.Errorf("CreateBucketSnapshot() = %+v, want name and bucket set", snap)
+	}
+
+	putTestObject(t, src, "state.txt", []byte("after"))
+
+	if _, err := client.ForkBucket(ctx, "source", "fork", WithSnapshotVersion(snap.Version)); err != nil {
+		t.Fatalf("ForkBucket() from snapshot failed: %v", err)
+	}
+
+	obj, err := client.For("fork").Get(ctx, "state.txt")
+	if err != nil {
+		t.Fatalf("Get() from fork failed: %v", err)
+	}
+	defer obj.Body.Close()
+
+	if data, _ := io.ReadAll(obj.Body); string(data) != "before" {
+		t.Errorf("Get() from fork = %q, want %q", data, "before")
+	}
+}
+
+func TestListBucketSnapshots_hermetic(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "source", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	descs := []string{"nightly", "before v2; a=b & c", "nightly"}
+	var created []*SnapshotInfo
+	for _, desc := range descs {
+		snap, err := client.CreateBucketSnapshot(ctx, "source", desc)
+		if err != nil {
+			t.Fatalf("CreateBucketSnapshot(%q) failed: %v", desc, err)
+		}
+		created = append(created, snap)
+	}
+
+	var listed []SnapshotInfo
+	var pages int
+	opts := []BucketOption{WithListLimit(2)}
+	for {
+		list, err := client.ListBucketSnapshots(ctx, "source", opts...)
+		if err != nil {
+			t.Fatalf("ListBucketSnapshots() failed: %v", err)
+		}
+		pages++
+		listed = append(listed, list.Snapshots...)
+		if !list.Truncated {
+			break
+		}
+		opts = []BucketOption{WithListLimit(2), WithListToken(list.NextToken)}
+	}
+
+	if pages != 2 {
+		t.Errorf("ListBucketSnapshots() took %d pages, want 2", pages)
+	}
+	if len(listed) != len(created) {
+		t.Fatalf("ListBucketSnapshots() returned %d snapshots, want %d", len(listed), len(created))
+	}
+	for i, snap := range listed {
+		if snap.Version != created[i].Version || snap.Name != created[i].Name {
+			t.Errorf("snapshot %d = %q (%q), want %q (%q)", i, snap.Version, snap.Name, created[i].Version, created[i].Name)
+		}
+		if snap.Bucket != "source" || snap.Created.IsZero() {
+			t.Errorf("snapshot %d = %+v, want bucket and creation time set", i, snap)
+		}
+	}
+
+	t.Run("find latest by name", func(t *testing.T) {
+		snap, err := client.FindSnapshot(ctx, "source", "nightly", WithListLimit(1))
+		if err != nil {
+			t.Fatalf("FindSnapshot() failed: %v", err)
+		}
+		if snap.Version != created[2].Version {
+			t.Errorf("FindSnapshot() = %q, want latest nightly %q", snap.Version, created[2].Version)
+		}
+	})
+
+	t.Run("find escaped name", func(t *testing.T) {
+		snap, err := client.FindSnapshot(ctx, "source", "before v2; a=b & c")
+		if err != nil {
+			t.Fatalf("FindSnapshot() failed: %v", err)
+		}
+		if _, err := client.ForkBucket(ctx, "source", "fork", WithSnapshotVersion(snap.Version)); err != nil {
+			t.Errorf("ForkBucket() from found snapshot failed: %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if _, err := client.FindSnapshot(ctx, "source", "missing"); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("FindSnapshot() error = %v, want ErrSnapshotNotFound", err)
+		}
+	})
+}
+
 // TestBucketLifecycle_integration tests the full bucket lifecycle with real Tigris operations.
 // This test requires TIGRIS_STORAGE_ACCESS_KEY_ID and TIGRIS_STORAGE_SECRET_ACCESS_KEY to be set.
 func TestBucketLifecycle_integration(t *testing.T) {
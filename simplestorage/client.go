@@ -2,21 +2,44 @@ package simplestorage
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
 )
 
 // ErrNoBucketName is returned when no bucket name is provided via the
 // TIGRIS_STORAGE_BUCKET environment variable or the WithBucket option.
 var ErrNoBucketName = errors.New("bucket name not set: provide the TIGRIS_STORAGE_BUCKET environment variable or use WithBucket option")
 
+// Errors from Tigris are wrapped so they can be checked with errors.Is against these
+// values, which are the same as the matching errors in the storage package. Use
+// errors.As with a *storage.Error to get the request ID for a support ticket.
+var (
+	// ErrObjectNotFound is returned when the object does not exist.
+	ErrObjectNotFound = storage.ErrObjectNotFound
+
+	// ErrPreconditionFailed is returned when a conditional request did not hold.
+	ErrPreconditionFailed = storage.ErrPreconditionFailed
+
+	// ErrAccessDenied is returned when the credentials are not allowed to make the call.
+	ErrAccessDenied = storage.ErrAccessDenied
+
+	// ErrSlowDown is returned when Tigris is throttling requests.
+	ErrSlowDown = storage.ErrSlowDown
+)
+
 // Client is a high-level client for Tigris that simplifies common interactions
 // to very high level calls.
 type Client struct {
@@ -58,7 +81,9 @@ func WithMaxKeys(maxKeys int32) ClientOption {
 	}
 }
 
-// WithDelimiter sets a delimiter for grouping keys in List calls.
+// WithDelimiter sets a delimiter for grouping keys in List calls. Keys that contain
+// the delimiter after the prefix are returned once per group in ListResult.Prefixes
+// instead of in Items.
 func WithDelimiter(delimiter string) ClientOption {
 	return func(co *ClientOptions) {
 		co.Delimiter = aws.String(delimiter)
@@ -79,20 +104,233 @@ func WithPaginationToken(token string) ClientOption {
 	}
 }
 
-// WithContentType sets the Content-Type header for presigned PUT URLs.
+// WithQuery filters List calls with a Tigris metadata query built with
+// tigrisheaders.Query.
+//
+// For more information, see the Tigris documentation[1].
+//
+// [1]: https://www.tigrisdata.com/docs/objects/query-metadata/
+func WithQuery(query *tigrisheaders.QueryBuilder) ClientOption {
+	return func(co *ClientOptions) {
+		co.Query = query
+	}
+}
+
+// WithContentType sets the Content-Type header for presigned PUT URLs and presigned
+// multipart uploads, or the new content type of the destination object in Copy.
 func WithContentType(contentType string) ClientOption {
 	return func(co *ClientOptions) {
 		co.ContentType = aws.String(contentType)
 	}
 }
 
-// WithContentDisposition sets the Content-Disposition header for presigned PUT URLs.
+// WithContentDisposition sets the Content-Disposition header for presigned PUT URLs
+// and presigned multipart uploads, or the new content disposition of the destination
+// object in Copy.
 func WithContentDisposition(disposition string) ClientOption {
 	return func(co *ClientOptions) {
 		co.ContentDisposition = aws.String(disposition)
 	}
 }
 
+// WithCacheControl sets the Cache-Control header for presigned PUT URLs and presigned
+// multipart uploads, or the new cache control of the destination object in Copy.
+func WithCacheControl(cacheControl string) ClientOption {
+	return func(co *ClientOptions) {
+		co.CacheControl = aws.String(cacheControl)
+	}
+}
+
+// WithContentMD5 signs the MD5 digest of the body into presigned PUT URLs, so Tigris
+// rejects uploads of any other content.
+func WithContentMD5(sum []byte) ClientOption {
+	return func(co *ClientOptions) {
+		co.ContentMD5 = aws.String(base64.StdEncoding.EncodeToString(sum))
+	}
+}
+
+// WithChecksumSHA256 signs the SHA-256 checksum of the body into presigned PUT URLs,
+// so Tigris rejects uploads of any other content.
+func WithChecksumSHA256(sum []byte) ClientOption {
+	return func(co *ClientOptions) {
+		co.ChecksumSHA256 = aws.String(base64.StdEncoding.EncodeToString(sum))
+	}
+}
+
+// WithReplicationRegions sets the regions that objects uploaded with presigned PUT URLs
+// are stored in.
+//
+// See the Tigris documentation[1] for more information.
+//
+// [1]: https://www.tigrisdata.com/docs/concepts/regions/
+func WithReplicationRegions(regions ...tigrisheaders.Region) ClientOption {
+	return func(co *ClientOptions) {
+		co.Regions = append(co.Regions, regions...)
+	}
+}
+
+// WithResponseContentType overrides the Content-Type of the response to presigned GET
+// URLs.
+func WithResponseContentType(contentType string) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseContentType = aws.String(contentType)
+	}
+}
+
+// WithResponseContentDisposition overrides the Content-Disposition of the response to
+// presigned GET URLs, for example to download the object under a friendly file name
+// with `attachment; filename="report.pdf"`.
+func WithResponseContentDisposition(disposition string) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseContentDisposition = aws.String(disposition)
+	}
+}
+
+// WithResponseCacheControl overrides the Cache-Control of the response to presigned
+// GET URLs.
+func WithResponseCacheControl(cacheControl string) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseCacheControl = aws.String(cacheControl)
+	}
+}
+
+// WithMultipartThreshold sets the object size at which Put switches from a single
+// PutObject call to a multipart upload. Bodies of unknown size (Object.Size is 0) that
+// turn out to be larger than one part are always uploaded in parts.
+func WithMultipartThreshold(size int64) ClientOption {
+	return func(co *ClientOptions) {
+		co.MultipartThreshold = size
+	}
+}
+
+// WithPartSize sets the size of each part in multipart uploads and ranged downloads.
+// Upload part sizes below MinPartSize are raised to MinPartSize.
+func WithPartSize(size int64) ClientOption {
+	return func(co *ClientOptions) {
+		co.PartSize = size
+	}
+}
+
+// WithConcurrency sets how many parts of a multipart transfer, how many requests of a
+// batch operation such as RenamePrefix or DeletePrefix, or how many files of a sync
+// are in flight at once.
+func WithConcurrency(n int) ClientOption {
+	return func(co *ClientOptions) {
+		co.Concurrency = n
+	}
+}
+
+// WithBlockCache makes readers returned by Open fetch whole blocks of blockSize bytes
+// and keep the most recently used blocks in memory, so repeated and nearby reads don't
+// each need a request to Tigris.
+func WithBlockCache(blockSize int64, blocks int) ClientOption {
+	return func(co *ClientOptions) {
+		co.BlockSize = blockSize
+		co.CacheBlocks = blocks
+	}
+}
+
+// WithMetadata sets the custom metadata of the destination object in Copy, or of the
+// object uploaded with a presigned PUT URL or a presigned multipart upload.
+func WithMetadata(metadata map[string]string) ClientOption {
+	return func(co *ClientOptions) {
+		co.Metadata = metadata
+	}
+}
+
+// WithSourceBucket makes Copy read the source object from bucket instead of the
+// Client's bucket, for copying between buckets.
+func WithSourceBucket(bucket string) ClientOption {
+	return func(co *ClientOptions) {
+		co.SourceBucket = bucket
+	}
+}
+
+// WithSourceVersion makes Copy read the given version of the source object.
+func WithSourceVersion(version string) ClientOption {
+	return func(co *ClientOptions) {
+		co.SourceVersion = version
+	}
+}
+
+// WithMetadataDirective sets whether Copy keeps the content headers and metadata of the
+// source object (types.MetadataDirectiveCopy, the default) or replaces them with the
+// ones set by WithContentType, WithContentDisposition, WithCacheControl and
+// WithMetadata (types.MetadataDirectiveReplace).
+func WithMetadataDirective(directive types.MetadataDirective) ClientOption {
+	return func(co *ClientOptions) {
+		co.MetadataDirective = directive
+	}
+}
+
+// WithSnapshot makes Get, Head, List, Open, Download, Copy and FS read objects as they
+// were in the given snapshot of the bucket. In Copy, it applies to the source object.
+func WithSnapshot(version string) ClientOption {
+	return func(co *ClientOptions) {
+		co.SnapshotVersion = version
+	}
+}
+
+// WithNoOverwrite makes Copy, Rename and RenamePrefix leave existing destination
+// objects alone instead of replacing them, and makes presigned PUT URLs and
+// CompleteMultipartUpload fail if the object already exists.
+func WithNoOverwrite() ClientOption {
+	return func(co *ClientOptions) {
+		co.NoOverwrite = true
+	}
+}
+
+// WithDryRun makes DeleteMany, DeletePrefix, SyncUpload and SyncDownload only report
+// what they would do, without changing anything.
+func WithDryRun() ClientOption {
+	return func(co *ClientOptions) {
+		co.DryRun = true
+	}
+}
+
+// WithMirror makes SyncUpload and SyncDownload delete objects or files in the
+// destination that are not in the source, like the --delete flag of rsync.
+func WithMirror() ClientOption {
+	return func(co *ClientOptions) {
+		co.Mirror = true
+	}
+}
+
+// WithInclude limits SyncUpload and SyncDownload to files whose paths, relative to
+// the directory or prefix, match at least one of the glob patterns. Patterns use
+// path.Match syntax plus "**" for any number of directories; a pattern without a
+// slash matches the file name at any depth.
+func WithInclude(patterns ...string) ClientOption {
+	return func(co *ClientOptions) {
+		co.Include = append(co.Include, patterns...)
+	}
+}
+
+// WithExclude makes SyncUpload and SyncDownload skip files whose paths match any of
+// the glob patterns, in the same syntax as WithInclude. Excluded files are neither
+// transferred nor deleted by WithMirror.
+func WithExclude(patterns ...string) ClientOption {
+	return func(co *ClientOptions) {
+		co.Exclude = append(co.Exclude, patterns...)
+	}
+}
+
+// WithChecksum makes SyncUpload and SyncDownload compare files of the same size by
+// content, using the MD5 in the object's ETag, instead of by modification time.
+func WithChecksum() ClientOption {
+	return func(co *ClientOptions) {
+		co.Checksum = true
+	}
+}
+
+// WithMaxAttempts sets how many read-modify-write cycles Update tries before giving
+// up when other writers keep changing the object.
+func WithMaxAttempts(n int) ClientOption {
+	return func(co *ClientOptions) {
+		co.MaxAttempts = n
+	}
+}
+
 // ClientOptions is the collection of options that are set for individual Tigris
 // calls.
 type ClientOptions struct {
@@ -105,19 +343,68 @@ type ClientOptions struct {
 	Delimiter       *string
 	Prefix          *string
 	PaginationToken *string
+	Query           *tigrisheaders.QueryBuilder
 
 	// Presign options
-	ContentType        *string
-	ContentDisposition *string
+	ContentType                *string
+	ContentDisposition         *string
+	CacheControl               *string
+	ContentMD5                 *string
+	ChecksumSHA256             *string
+	Regions                    []tigrisheaders.Region
+	ResponseContentType        *string
+	ResponseContentDisposition *string
+	ResponseCacheControl       *string
+	PostConditions             []PostCondition
+
+	// Transfer options
+	MultipartThreshold int64
+	PartSize           int64
+	Concurrency        int
+
+	// Reader options
+	BlockSize   int64
+	CacheBlocks int
+
+	// Update options
+	MaxAttempts int
+
+	// Copy and rename options
+	Metadata          map[string]string
+	MetadataDirective types.MetadataDirective
+	SourceBucket      string
+	SourceVersion     string
+	SnapshotVersion   string
+	NoOverwrite       bool
+
+	// Delete and sync options
+	DryRun   bool
+	Mirror   bool
+	Include  []string
+	Exclude  []string
+	Checksum bool
 }
 
 // defaults populates client options from the global Options.
 func (ClientOptions) defaults(o Options) ClientOptions {
 	return ClientOptions{
-		BucketName: o.BucketName,
+		BucketName:         o.BucketName,
+		MultipartThreshold: DefaultMultipartThreshold,
+		PartSize:           DefaultPartSize,
+		Concurrency:        DefaultConcurrency,
+		MaxAttempts:        DefaultMaxAttempts,
 	}
 }
 
+// readOptions returns the S3 options for calls that read objects, which honour
+// WithSnapshot.
+func (co ClientOptions) readOptions() []func(*s3.Options) {
+	if co.SnapshotVersion == "" {
+		return co.S3Options
+	}
+	return append(slices.Clip(co.S3Options), tigrisheaders.WithSnapshotVersion(co.SnapshotVersion))
+}
+
 // New creates a new Client based on the options provided and defaults loaded from the environment.
 //
 // By default New reads the following environment variables for setting its defaults:
@@ -202,6 +489,7 @@ type Object struct {
 // ListResult contains the result of a List operation, including pagination information.
 type ListResult struct {
 	Items     []Object // List of objects
+	Prefixes  []string // Common prefixes of the keys grouped by WithDelimiter
 	NextToken string   // Pagination token for the next page
 	HasMore   bool     // Whether there are more objects to list
 }
@@ -220,11 +508,11 @@ func (c *Client) Get(ctx context.Context, key string, opts ...ClientOption) (*Ob
 			Bucket: aws.String(o.BucketName),
 			Key:    aws.String(key),
 		},
-		o.S3Options...,
+		o.readOptions()...,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("simplestorage: can't get %s/%s: %v", o.BucketName, key, err)
+		return nil, fmt.Errorf("simplestorage: can't get %s/%s: %w", o.BucketName, key, err)
 	}
 
 	return &Object{
@@ -248,17 +536,27 @@ func (c *Client) Head(ctx context.Context, key string, opts ...ClientOption) (*O
 		doer(&o)
 	}
 
+	obj, err := c.headObject(ctx, key, o)
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't head %s/%s: %w", o.BucketName, key, err)
+	}
+
+	return obj, nil
+}
+
+// headObject fetches the metadata of key, returning errors from Tigris unwrapped.
+func (c *Client) headObject(ctx context.Context, key string, o ClientOptions) (*Object, error) {
 	resp, err := c.cli.HeadObject(
 		ctx,
 		&s3.HeadObjectInput{
 			Bucket: aws.String(o.BucketName),
 			Key:    aws.String(key),
 		},
-		o.S3Options...,
+		o.readOptions()...,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("simplestorage: can't head %s/%s: %v", o.BucketName, key, err)
+		return nil, err
 	}
 
 	return &Object{
@@ -275,6 +573,13 @@ func (c *Client) Head(ctx context.Context, key string, opts ...ClientOption) (*O
 }
 
 // Put puts the contents of an object into Tigris.
+//
+// Objects of at least the multipart threshold (see WithMultipartThreshold) and bodies
+// of unknown size (Object.Size is 0) are uploaded as multipart uploads, with parts sent
+// in parallel (see WithPartSize and WithConcurrency). If any part fails, the upload is
+// aborted. Options set with WithS3Options are applied to every call of the multipart
+// upload, except that If-Match and If-None-Match conditions, such as those of
+// tigrisheaders.WithCreateObjectIfNotExists, are only sent when it is completed.
 func (c *Client) Put(ctx context.Context, obj *Object, opts ...ClientOption) (*Object, error) {
 	o := new(ClientOptions).defaults(c.options)
 
@@ -282,6 +587,10 @@ func (c *Client) Put(ctx context.Context, obj *Object, opts ...ClientOption) (*O
 		doer(&o)
 	}
 
+	if obj.Size <= 0 || obj.Size >= o.MultipartThreshold {
+		return c.putMultipart(ctx, obj, o)
+	}
+
 	resp, err := c.cli.PutObject(
 		ctx,
 		&s3.PutObjectInput{
@@ -295,7 +604,7 @@ func (c *Client) Put(ctx context.Context, obj *Object, opts ...ClientOption) (*O
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("simplestorage: can't put %s/%s: %v", o.BucketName, obj.Key, err)
+		return nil, fmt.Errorf("simplestorage: can't put %s/%s: %w", o.BucketName, obj.Key, err)
 	}
 
 	obj.Bucket = o.BucketName
@@ -321,7 +630,7 @@ func (c *Client) Delete(ctx context.Context, key string, opts ...ClientOption) e
 		},
 		o.S3Options...,
 	); err != nil {
-		return fmt.Errorf("simplestorage: can't delete %s/%s: %v", o.BucketName, key, err)
+		return fmt.Errorf("simplestorage: can't delete %s/%s: %w", o.BucketName, key, err)
 	}
 
 	return nil
@@ -339,6 +648,14 @@ func (c *Client) List(ctx context.Context, opts ...ClientOption) (*ListResult, e
 		doer(&o)
 	}
 
+	if o.Query != nil {
+		query, err := o.Query.Build()
+		if err != nil {
+			return nil, fmt.Errorf("simplestorage: can't list %s: %w", o.BucketName, err)
+		}
+		o.S3Options = append(o.S3Options, tigrisheaders.WithQuery(query))
+	}
+
 	resp, err := c.cli.ListObjectsV2(
 		ctx,
 		&s3.ListObjectsV2Input{
@@ -349,11 +666,11 @@ func (c *Client) List(ctx context.Context, opts ...ClientOption) (*ListResult, e
 			ContinuationToken: o.PaginationToken,
 			StartAfter:        o.StartAfter,
 		},
-		o.S3Options...,
+		o.readOptions()...,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("simplestorage: can't list %s: %v", o.BucketName, err)
+		return nil, fmt.Errorf("simplestorage: can't list %s: %w", o.BucketName, err)
 	}
 
 	result := &ListResult{
@@ -373,35 +690,114 @@ func (c *Client) List(ctx context.Context, opts ...ClientOption) (*ListResult, e
 		})
 	}
 
+	for _, p := range resp.CommonPrefixes {
+		result.Prefixes = append(result.Prefixes, lower(p.Prefix, ""))
+	}
+
 	return result, nil
 }
 
+// ListAll returns an iterator over every object matching the given criteria,
+// fetching pages lazily as the loop advances. WithMaxKeys sets the page size;
+// WithPrefix, WithDelimiter, WithStartAfter and WithQuery work as in List.
+//
+// If a page can't be fetched or ctx is cancelled, the iterator yields the error once
+// and stops. Breaking out of the loop stops it without fetching further pages.
+//
+//	for obj, err := range client.ListAll(ctx, simplestorage.WithPrefix("logs/")) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(obj.Key)
+//	}
+func (c *Client) ListAll(ctx context.Context, opts ...ClientOption) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		pageOpts := opts
+
+		for {
+			resp, err := c.List(ctx, pageOpts...)
+			if err != nil {
+				yield(Object{}, err)
+				return
+			}
+
+			for _, obj := range resp.Items {
+				if err := ctx.Err(); err != nil {
+					yield(Object{}, err)
+					return
+				}
+				if !yield(obj, nil) {
+					return
+				}
+			}
+
+			if !resp.HasMore || resp.NextToken == "" {
+				return
+			}
+
+			pageOpts = append(slices.Clip(opts), WithPaginationToken(resp.NextToken))
+		}
+	}
+}
+
+// PresignedRequest is a presigned request for an object.
+type PresignedRequest struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the presigned URL.
+	URL string
+
+	// Header holds the signed headers that the request must be sent with. Tigris
+	// rejects the request if any of them is missing or has a different value.
+	Header http.Header
+}
+
 // PresignURL generates a presigned URL for the specified HTTP method, key, and expiry duration.
 //
 // The following HTTP methods are supported:
 //   - http.MethodGet: Generate a URL for downloading an object
+//   - http.MethodHead: Generate a URL for reading the metadata of an object
 //   - http.MethodPut: Generate a URL for uploading an object
 //   - http.MethodDelete: Generate a URL for deleting an object
 //
-// For PUT operations, use WithContentType() and WithContentDisposition() to set headers.
+// For GET operations, use WithResponseContentDisposition(), WithResponseContentType() and
+// WithResponseCacheControl() to override the response headers.
+//
+// For PUT operations, use WithContentType(), WithContentDisposition(), WithCacheControl(),
+// WithMetadata(), WithContentMD5(), WithChecksumSHA256(), WithReplicationRegions() and
+// WithNoOverwrite() to sign headers into the URL. The uploader must send them with the
+// exact same values; use PresignRequest to get them.
+// For uploads from an HTML form, use PresignPost instead.
 //
 // The expiry duration must be positive; the returned URL will only be valid for this duration.
 func (c *Client) PresignURL(ctx context.Context, method string, key string, expiry time.Duration, opts ...ClientOption) (string, error) {
+	req, err := c.PresignRequest(ctx, method, key, expiry, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return req.URL, nil
+}
+
+// PresignRequest is like PresignURL, but also returns the signed headers that the
+// request must be sent with.
+func (c *Client) PresignRequest(ctx context.Context, method string, key string, expiry time.Duration, opts ...ClientOption) (*PresignedRequest, error) {
 	// Validate HTTP method
 	switch method {
-	case http.MethodGet, http.MethodPut, http.MethodDelete:
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
 	default:
-		return "", fmt.Errorf("simplestorage: unsupported HTTP method %q for presigned URL (supported: GET, PUT, DELETE)", method)
+		return nil, fmt.Errorf("simplestorage: unsupported HTTP method %q for presigned URL (supported: GET, HEAD, PUT, DELETE)", method)
 	}
 
 	// Validate key
 	if key == "" {
-		return "", fmt.Errorf("simplestorage: key cannot be empty for presigned URL")
+		return nil, fmt.Errorf("simplestorage: key cannot be empty for presigned URL")
 	}
 
 	// Validate expiry
 	if expiry <= 0 {
-		return "", fmt.Errorf("simplestorage: invalid expiry duration %v for presigned URL (must be positive)", expiry)
+		return nil, fmt.Errorf("simplestorage: invalid expiry duration %v for presigned URL (must be positive)", expiry)
 	}
 
 	// Build options
@@ -414,16 +810,32 @@ func (c *Client) PresignURL(ctx context.Context, method string, key string, expi
 	presignClient := s3.NewPresignClient(c.cli.Client)
 
 	// Route to appropriate presign method
+	var (
+		req *v4.PresignedHTTPRequest
+		err error
+	)
 	switch method {
 	case http.MethodGet:
-		return presignURLGet(ctx, presignClient, o.BucketName, key, expiry)
+		req, err = presignURLGet(ctx, presignClient, o.BucketName, key, expiry, o)
+	case http.MethodHead:
+		req, err = presignURLHead(ctx, presignClient, o.BucketName, key, expiry, o)
 	case http.MethodPut:
-		return presignURLPut(ctx, presignClient, o.BucketName, key, expiry, o)
+		req, err = presignURLPut(ctx, presignClient, o.BucketName, key, expiry, o)
 	case http.MethodDelete:
-		return presignURLDelete(ctx, presignClient, o.BucketName, key, expiry)
+		req, err = presignURLDelete(ctx, presignClient, o.BucketName, key, expiry, o)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	header := req.SignedHeader.Clone()
+	header.Del("Host")
 
-	return "", nil // unreachable
+	return &PresignedRequest{
+		Method: req.Method,
+		URL:    req.URL,
+		Header: header,
+	}, nil
 }
 
 // lower lowers the "pointer level" of the value by returning the value pointed
@@ -446,50 +858,77 @@ func raise[T comparable](v T) *T {
 }
 
 // presignURLGet generates a presigned URL for GET operations.
-func presignURLGet(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration) (string, error) {
+func presignURLGet(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration, opts ClientOptions) (*v4.PresignedHTTPRequest, error) {
 	presignResult, err := client.PresignGetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-	}, s3.WithPresignExpires(expiry))
+		Bucket:                     aws.String(bucket),
+		Key:                        aws.String(key),
+		ResponseContentType:        opts.ResponseContentType,
+		ResponseContentDisposition: opts.ResponseContentDisposition,
+		ResponseCacheControl:       opts.ResponseCacheControl,
+	}, s3.WithPresignExpires(expiry), s3.WithPresignClientFromClientOptions(opts.S3Options...))
 	if err != nil {
-		return "", fmt.Errorf("presign get: %w", err)
+		return nil, fmt.Errorf("presign get: %w", err)
 	}
 
-	return presignResult.URL, nil
+	return presignResult, nil
 }
 
-// presignURLPut generates a presigned URL for PUT operations.
-func presignURLPut(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration, opts ClientOptions) (string, error) {
-	input := &s3.PutObjectInput{
+// presignURLHead generates a presigned URL for HEAD operations.
+func presignURLHead(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration, opts ClientOptions) (*v4.PresignedHTTPRequest, error) {
+	presignResult, err := client.PresignHeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expiry), s3.WithPresignClientFromClientOptions(opts.S3Options...))
+	if err != nil {
+		return nil, fmt.Errorf("presign head: %w", err)
 	}
 
-	// Apply optional headers
+	return presignResult, nil
+}
+
+// presignURLPut generates a presigned URL for PUT operations.
+func presignURLPut(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration, opts ClientOptions) (*v4.PresignedHTTPRequest, error) {
+	// Optional headers are signed into the URL when set
+	input := &s3.PutObjectInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		ContentDisposition: opts.ContentDisposition,
+		CacheControl:       opts.CacheControl,
+		ContentMD5:         opts.ContentMD5,
+		ChecksumSHA256:     opts.ChecksumSHA256,
+		Metadata:           opts.Metadata,
+	}
+
+	// The presigner drops Content-Type from requests without a body, so add it
+	// after that happens
+	s3Opts := opts.S3Options
 	if opts.ContentType != nil {
-		input.ContentType = opts.ContentType
+		s3Opts = append(slices.Clip(s3Opts), tigrisheaders.WithHeader("Content-Type", *opts.ContentType))
+	}
+	if len(opts.Regions) != 0 {
+		s3Opts = append(slices.Clip(s3Opts), tigrisheaders.WithStaticReplicationRegions(opts.Regions))
 	}
-	if opts.ContentDisposition != nil {
-		input.ContentDisposition = opts.ContentDisposition
+	if opts.NoOverwrite {
+		s3Opts = append(slices.Clip(s3Opts), tigrisheaders.WithCreateObjectIfNotExists())
 	}
 
-	presignResult, err := client.PresignPutObject(ctx, input, s3.WithPresignExpires(expiry))
+	presignResult, err := client.PresignPutObject(ctx, input, s3.WithPresignExpires(expiry), s3.WithPresignClientFromClientOptions(s3Opts...))
 	if err != nil {
-		return "", fmt.Errorf("presign put: %w", err)
+		return nil, fmt.Errorf("presign put: %w", err)
 	}
 
-	return presignResult.URL, nil
+	return presignResult, nil
 }
 
 // presignURLDelete generates a presigned URL for DELETE operations.
-func presignURLDelete(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration) (string, error) {
+func presignURLDelete(ctx context.Context, client *s3.PresignClient, bucket, key string, expiry time.Duration, opts ClientOptions) (*v4.PresignedHTTPRequest, error) {
 	presignResult, err := client.PresignDeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
-	}, s3.WithPresignExpires(expiry))
+	}, s3.WithPresignExpires(expiry), s3.WithPresignClientFromClientOptions(opts.S3Options...))
 	if err != nil {
-		return "", fmt.Errorf("presign delete: %w", err)
+		return nil, fmt.Errorf("presign delete: %w", err)
 	}
 
-	return presignResult.URL, nil
+	return presignResult, nil
 }
@@ -3,12 +3,69 @@ package simplestorage
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	_ "github.com/joho/godotenv/autoload"
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+	"github.com/tigrisdata/storage-go/tigristest"
 )
 
+// newTestClient returns a Client backed by an in-process fake Tigris server, with
+// its default bucket already created.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	srv := tigristest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := New(context.Background(),
+		WithBucket("test-bucket"),
+		WithEndpoint(srv.URL),
+		WithPathStyle(true),
+		WithAccessKeypair("test", "test"),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if _, err := client.CreateBucket(context.Background(), "test-bucket"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	return client
+}
+
+// readTestObject returns the contents of the object at key.
+func readTestObject(t *testing.T, client *Client, key string) string {
+	t.Helper()
+
+	obj, err := client.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q) failed: %v", key, err)
+	}
+	defer obj.Body.Close()
+
+	data, err := io.ReadAll(obj.Body)
+	if err != nil {
+		t.Fatalf("reading %q failed: %v", key, err)
+	}
+	return string(data)
+}
+
+// seekableBody wraps a string in an io.ReadCloser that also implements io.Seeker,
+// which lets the AWS SDK checksum request bodies sent over plain HTTP.
+func seekableBody(s string) io.ReadCloser {
+	return struct {
+		io.ReadSeeker
+		io.Closer
+	}{strings.NewReader(s), io.NopCloser(nil)}
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -192,3 +249,313 @@ func TestClient_For(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_objectRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	put, err := client.Put(ctx, &Object{
+		Key:         "docs/readme.txt",
+		ContentType: "text/plain",
+		Size:        int64(len("hello, world")),
+		Body:        seekableBody("hello, world"),
+	})
+	if err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	if put.Etag == "" {
+		t.Error("Put() returned an empty ETag")
+	}
+
+	got, err := client.Get(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	data, _ := io.ReadAll(got.Body)
+	got.Body.Close()
+	if string(data) != "hello, world" {
+		t.Errorf("Get() body = %q, want %q", data, "hello, world")
+	}
+	if got.ContentType != "text/plain" || got.Etag != put.Etag {
+		t.Errorf("Get() = {ContentType: %q, Etag: %q}, want {text/plain, %s}", got.ContentType, got.Etag, put.Etag)
+	}
+
+	head, err := client.Head(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatalf("Head() failed: %v", err)
+	}
+	if head.Size != int64(len("hello, world")) {
+		t.Errorf("Head() size = %d, want %d", head.Size, len("hello, world"))
+	}
+
+	list, err := client.List(ctx, WithPrefix("docs/"))
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Key != "docs/readme.txt" {
+		t.Errorf("List() = %+v, want [docs/readme.txt]", list.Items)
+	}
+
+	if err := client.Delete(ctx, "docs/readme.txt"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := client.Head(ctx, "docs/readme.txt"); err == nil {
+		t.Error("Head() after Delete() succeeded, want error")
+	}
+}
+
+func TestClient_List_withQuery(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	for key, ct := range map[string]string{"a.png": "image/png", "b.txt": "text/plain", "c.png": "image/png"} {
+		if _, err := client.Put(ctx, &Object{Key: key, ContentType: ct, Body: seekableBody(key)}); err != nil {
+			t.Fatalf("Put(%s) failed: %v", key, err)
+		}
+	}
+
+	list, err := client.List(ctx, WithQuery(tigrisheaders.Query().Where(tigrisheaders.FieldContentType).Eq("image/png")))
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("List() with query returned %d items, want 2", len(list.Items))
+	}
+
+	_, err = client.List(ctx, WithQuery(tigrisheaders.Query().Where("$bogus").Eq("x")))
+	if !errors.Is(err, tigrisheaders.ErrInvalidQuery) {
+		t.Errorf("List() with invalid query error = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestClient_List_withDelimiter(t *testing.T) {
+	client := newTestClient(t)
+	for _, key := range []string{"a/1", "a/sub/2", "a/sub/3", "a/other/4", "b/5"} {
+		putTestObject(t, client, key, []byte(key))
+	}
+
+	list, err := client.List(context.Background(), WithPrefix("a/"), WithDelimiter("/"))
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	var keys []string
+	for _, obj := range list.Items {
+		keys = append(keys, obj.Key)
+	}
+
+	if !slices.Equal(keys, []string{"a/1"}) {
+		t.Errorf("List() items = %v, want [a/1]", keys)
+	}
+	if !slices.Equal(list.Prefixes, []string{"a/other/", "a/sub/"}) {
+		t.Errorf("List() prefixes = %v, want [a/other/ a/sub/]", list.Prefixes)
+	}
+}
+
+func TestClient_ListAll(t *testing.T) {
+	client := newTestClient(t)
+	for _, key := range []string{"a/1", "a/2", "a/3", "a/sub/4", "a/sub/5", "b/1", "c"} {
+		putTestObject(t, client, key, []byte(key))
+	}
+
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want []string
+	}{
+		{
+			name: "everything in one page",
+			want: []string{"a/1", "a/2", "a/3", "a/sub/4", "a/sub/5", "b/1", "c"},
+		},
+		{
+			name: "small pages",
+			opts: []ClientOption{WithMaxKeys(2)},
+			want: []string{"a/1", "a/2", "a/3", "a/sub/4", "a/sub/5", "b/1", "c"},
+		},
+		{
+			name: "prefix across pages",
+			opts: []ClientOption{WithPrefix("a/"), WithMaxKeys(1)},
+			want: []string{"a/1", "a/2", "a/3", "a/sub/4", "a/sub/5"},
+		},
+		{
+			name: "delimiter skips nested objects",
+			opts: []ClientOption{WithPrefix("a/"), WithDelimiter("/"), WithMaxKeys(1)},
+			want: []string{"a/1", "a/2", "a/3"},
+		},
+		{
+			name: "start after",
+			opts: []ClientOption{WithStartAfter("a/sub/5"), WithMaxKeys(1)},
+			want: []string{"b/1", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for obj, err := range client.ListAll(context.Background(), tt.opts...) {
+				if err != nil {
+					t.Fatalf("ListAll() failed: %v", err)
+				}
+				got = append(got, obj.Key)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ListAll_stops(t *testing.T) {
+	client := newTestClient(t)
+	for _, key := range []string{"1", "2", "3", "4", "5"} {
+		putTestObject(t, client, key, []byte(key))
+	}
+
+	t.Run("break", func(t *testing.T) {
+		var got []string
+		for obj, err := range client.ListAll(context.Background(), WithMaxKeys(2)) {
+			if err != nil {
+				t.Fatalf("ListAll() failed: %v", err)
+			}
+			got = append(got, obj.Key)
+			if len(got) == 3 {
+				break
+			}
+		}
+
+		if !slices.Equal(got, []string{"1", "2", "3"}) {
+			t.Errorf("ListAll() = %v, want [1 2 3]", got)
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			got  []string
+			errs []error
+		)
+		for obj, err := range client.ListAll(ctx, WithMaxKeys(2)) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			got = append(got, obj.Key)
+			cancel()
+		}
+
+		if len(got) != 1 {
+			t.Errorf("ListAll() yielded %v after cancel, want one object", got)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+			t.Errorf("ListAll() errors = %v, want one context.Canceled", errs)
+		}
+	})
+}
+
+func TestClient_errors(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "exists", []byte("data"))
+
+	if _, err := client.CreateBucket(ctx, "plain"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{
+			name: "get missing object",
+			call: func() error {
+				_, err := client.Get(ctx, "missing")
+				return err
+			},
+			want: ErrObjectNotFound,
+		},
+		{
+			name: "head missing object",
+			call: func() error {
+				_, err := client.Head(ctx, "missing")
+				return err
+			},
+			want: ErrObjectNotFound,
+		},
+		{
+			name: "open missing object",
+			call: func() error {
+				_, err := client.Open(ctx, "missing")
+				return err
+			},
+			want: ErrObjectNotFound,
+		},
+		{
+			name: "list missing bucket",
+			call: func() error {
+				_, err := client.List(ctx, OverrideBucket("missing"))
+				return err
+			},
+			want: ErrBucketNotFound,
+		},
+		{
+			name: "bucket info of missing bucket",
+			call: func() error {
+				_, err := client.GetBucketInfo(ctx, "missing")
+				return err
+			},
+			want: ErrBucketNotFound,
+		},
+		{
+			name: "create existing bucket",
+			call: func() error {
+				_, err := client.CreateBucket(ctx, "plain")
+				return err
+			},
+			want: ErrBucketAlreadyExists,
+		},
+		{
+			name: "delete non-empty bucket",
+			call: func() error {
+				return client.DeleteBucket(ctx, "test-bucket")
+			},
+			want: ErrBucketNotEmpty,
+		},
+		{
+			name: "snapshot without snapshots enabled",
+			call: func() error {
+				_, err := client.CreateBucketSnapshot(ctx, "plain", "nope")
+				return err
+			},
+			want: ErrSnapshotsNotEnabled,
+		},
+		{
+			name: "fork without snapshots enabled",
+			call: func() error {
+				_, err := client.ForkBucket(ctx, "plain", "fork")
+				return err
+			},
+			want: ErrSnapshotsNotEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+
+			var tigrisErr *storage.Error
+			if !errors.As(err, &tigrisErr) {
+				t.Fatalf("error %v does not wrap a *storage.Error", err)
+			}
+			if tigrisErr.RequestID == "" {
+				t.Error("storage.Error has no RequestID")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+// ErrNotModified is returned by GetIfNoneMatch when the object still has the given
+// ETag. It is the same value as storage.ErrNotModified.
+var ErrNotModified = storage.ErrNotModified
+
+// ConditionError is returned by the conditional methods of Client when the object's
+// current state means the call was not carried out. It matches ErrPreconditionFailed
+// (or ErrNotModified for GetIfNoneMatch) with errors.Is, and carries the object's
+// current ETag so the caller can decide how to retry.
+type ConditionError struct {
+	Bucket string // Bucket the object is in
+	Key    string // Key for the object
+	Etag   string // Current ETag of the object, or empty if it does not exist
+
+	kind  error
+	cause error
+}
+
+// Error implements error.
+func (e *ConditionError) Error() string {
+	current := "object does not exist"
+	if e.Etag != "" {
+		current = "current ETag " + e.Etag
+	}
+	return fmt.Sprintf("simplestorage: %s/%s: %v (%s)", e.Bucket, e.Key, e.kind, current)
+}
+
+// Unwrap returns ErrPreconditionFailed or ErrNotModified, and the error from Tigris.
+func (e *ConditionError) Unwrap() []error {
+	return []error{e.kind, e.cause}
+}
+
+// PutIfAbsent puts an object only if no object exists at its key. If one does, it
+// returns a *ConditionError matching ErrPreconditionFailed with the existing
+// object's ETag.
+func (c *Client) PutIfAbsent(ctx context.Context, obj *Object, opts ...ClientOption) (*Object, error) {
+	return c.putIf(ctx, obj, tigrisheaders.WithCreateObjectIfNotExists(), opts)
+}
+
+// PutIfMatch puts an object only if the object currently at its key has the given
+// ETag. Otherwise, including when there is no object at the key, it returns a
+// *ConditionError matching ErrPreconditionFailed with the current ETag.
+//
+// Use it to update an object read earlier without overwriting a concurrent change:
+//
+//	cur, err := client.Get(ctx, key)
+//	// ... compute the new contents from cur ...
+//	_, err = client.PutIfMatch(ctx, updated, cur.Etag)
+func (c *Client) PutIfMatch(ctx context.Context, obj *Object, etag string, opts ...ClientOption) (*Object, error) {
+	return c.putIf(ctx, obj, tigrisheaders.WithIfEtagMatches(etag), opts)
+}
+
+// putIf puts obj with the given precondition, turning a failed precondition into a
+// *ConditionError.
+func (c *Client) putIf(ctx context.Context, obj *Object, cond func(*s3.Options), opts []ClientOption) (*Object, error) {
+	result, err := c.Put(ctx, obj, append(slices.Clip(opts), WithS3Options(cond))...)
+	if err != nil {
+		if errors.Is(err, ErrPreconditionFailed) || errors.Is(err, ErrObjectNotFound) {
+			return nil, c.conditionFailed(ctx, obj.Key, err, opts)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteIfMatch deletes an object only if it currently has the given ETag.
+// Otherwise, including when there is no object at the key, it returns a
+// *ConditionError matching ErrPreconditionFailed with the current ETag.
+func (c *Client) DeleteIfMatch(ctx context.Context, key, etag string, opts ...ClientOption) error {
+	err := c.Delete(ctx, key, append(slices.Clip(opts), WithS3Options(tigrisheaders.WithIfEtagMatches(etag)))...)
+	if err != nil {
+		if errors.Is(err, ErrPreconditionFailed) || errors.Is(err, ErrObjectNotFound) {
+			return c.conditionFailed(ctx, key, err, opts)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetIfNoneMatch gets an object only if its ETag differs from etag, such as the ETag
+// of a cached copy. If the object is unchanged it returns a *ConditionError matching
+// ErrNotModified and no request body is transferred.
+func (c *Client) GetIfNoneMatch(ctx context.Context, key, etag string, opts ...ClientOption) (*Object, error) {
+	obj, err := c.Get(ctx, key, append(slices.Clip(opts), WithS3Options(tigrisheaders.WithIfEtagDoesNotMatch(etag)))...)
+	if err != nil {
+		if errors.Is(err, ErrNotModified) {
+			o := new(ClientOptions).defaults(c.options)
+			for _, doer := range opts {
+				doer(&o)
+			}
+
+			return nil, &ConditionError{
+				Bucket: o.BucketName,
+				Key:    key,
+				Etag:   etag,
+				kind:   ErrNotModified,
+				cause:  err,
+			}
+		}
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// conditionFailed builds a *ConditionError matching ErrPreconditionFailed for key,
+// looking up the object's current ETag with a consistent read of the live bucket.
+func (c *Client) conditionFailed(ctx context.Context, key string, cause error, opts []ClientOption) error {
+	o := new(ClientOptions).defaults(c.options)
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	ce := &ConditionError{
+		Bucket: o.BucketName,
+		Key:    key,
+		kind:   ErrPreconditionFailed,
+		cause:  cause,
+	}
+
+	o.SnapshotVersion = ""
+	o.S3Options = append(slices.Clip(o.S3Options), tigrisheaders.WithCompareAndSwap())
+	if cur, err := c.headObject(ctx, key, o); err == nil {
+		ce.Etag = cur.Etag
+	}
+
+	return ce
+}
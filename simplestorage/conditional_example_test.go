@@ -0,0 +1,116 @@
+package simplestorage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_PutIfMatch() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cur, err := client.Get(ctx, "counters/visits")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := io.ReadAll(cur.Body)
+	cur.Body.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	next := append(data, '!')
+
+	// Only write if nobody else changed the object since we read it
+	_, err = client.PutIfMatch(ctx, &simplestorage.Object{
+		Key:  "counters/visits",
+		Size: int64(len(next)),
+		Body: io.NopCloser(bytes.NewReader(next)),
+	}, cur.Etag)
+
+	var conflict *simplestorage.ConditionError
+	switch {
+	case errors.As(err, &conflict):
+		fmt.Println("Lost the race, object is now at", conflict.Etag)
+	case err != nil:
+		log.Fatal(err) // handle the error here
+	}
+}
+
+func ExampleClient_GetIfNoneMatch() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cachedEtag := `"9bb58f26192e4ba00f01e2e7b136bbd8"`
+
+	obj, err := client.GetIfNoneMatch(ctx, "config.json", cachedEtag)
+	if errors.Is(err, simplestorage.ErrNotModified) {
+		fmt.Println("Cached copy is still current")
+		return
+	}
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+	defer obj.Body.Close()
+
+	fmt.Println("Config changed, new ETag:", obj.Etag)
+}
+
+func ExampleClient_Update() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	type config struct {
+		Features map[string]bool `json:"features"`
+	}
+
+	// Safe to run from many processes at once: conflicting writes are retried
+	_, err = client.Update(ctx, "config/app.json", func(old *simplestorage.Object) (*simplestorage.Object, error) {
+		cfg := config{Features: map[string]bool{}}
+		if old != nil {
+			if err := json.NewDecoder(old.Body).Decode(&cfg); err != nil {
+				return nil, err
+			}
+		}
+
+		cfg.Features["dark-mode"] = true
+
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &simplestorage.Object{
+			ContentType: "application/json",
+			Size:        int64(len(data)),
+			Body:        io.NopCloser(bytes.NewReader(data)),
+		}, nil
+	})
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+}
@@ -0,0 +1,149 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestClient_conditionalWrites(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	put := func(key, body string) *Object {
+		return &Object{Key: key, Size: int64(len(body)), Body: seekableBody(body)}
+	}
+
+	first, err := client.PutIfAbsent(ctx, put("state.json", "v1"))
+	if err != nil {
+		t.Fatalf("PutIfAbsent() of a new key failed: %v", err)
+	}
+
+	_, err = client.PutIfAbsent(ctx, put("state.json", "v1 again"))
+	var ce *ConditionError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("PutIfAbsent() of an existing key error = %v, want *ConditionError matching ErrPreconditionFailed", err)
+	}
+	if ce.Etag != first.Etag || ce.Key != "state.json" || ce.Bucket != "test-bucket" {
+		t.Errorf("ConditionError = %+v, want current ETag %s", ce, first.Etag)
+	}
+
+	second, err := client.PutIfMatch(ctx, put("state.json", "v2"), first.Etag)
+	if err != nil {
+		t.Fatalf("PutIfMatch() with the current ETag failed: %v", err)
+	}
+
+	_, err = client.PutIfMatch(ctx, put("state.json", "v3"), first.Etag)
+	if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("PutIfMatch() with a stale ETag error = %v, want *ConditionError", err)
+	}
+	if ce.Etag != second.Etag {
+		t.Errorf("ConditionError.Etag = %s, want %s", ce.Etag, second.Etag)
+	}
+
+	_, err = client.PutIfMatch(ctx, put("missing.json", "v1"), first.Etag)
+	if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("PutIfMatch() of a missing key error = %v, want *ConditionError", err)
+	}
+	if ce.Etag != "" {
+		t.Errorf("ConditionError.Etag = %s for a missing key, want empty", ce.Etag)
+	}
+
+	err = client.DeleteIfMatch(ctx, "state.json", first.Etag)
+	if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("DeleteIfMatch() with a stale ETag error = %v, want *ConditionError", err)
+	}
+	if ce.Etag != second.Etag {
+		t.Errorf("ConditionError.Etag = %s, want %s", ce.Etag, second.Etag)
+	}
+
+	if err := client.DeleteIfMatch(ctx, "state.json", second.Etag); err != nil {
+		t.Fatalf("DeleteIfMatch() with the current ETag failed: %v", err)
+	}
+	if _, err := client.Head(ctx, "state.json"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Head() after DeleteIfMatch() error = %v, want ErrObjectNotFound", err)
+	}
+}
+
+func TestClient_GetIfNoneMatch(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "page.html", []byte("<h1>hi</h1>"))
+
+	obj, err := client.GetIfNoneMatch(ctx, "page.html", `"stale"`)
+	if err != nil {
+		t.Fatalf("GetIfNoneMatch() with a stale ETag failed: %v", err)
+	}
+	data, _ := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	if string(data) != "<h1>hi</h1>" {
+		t.Errorf("GetIfNoneMatch() body = %q", data)
+	}
+
+	_, err = client.GetIfNoneMatch(ctx, "page.html", obj.Etag)
+	var ce *ConditionError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrNotModified) {
+		t.Fatalf("GetIfNoneMatch() with the current ETag error = %v, want *ConditionError matching ErrNotModified", err)
+	}
+	if errors.Is(err, ErrPreconditionFailed) {
+		t.Error("not-modified error matches ErrPreconditionFailed")
+	}
+	if ce.Etag != obj.Etag {
+		t.Errorf("ConditionError.Etag = %s, want %s", ce.Etag, obj.Etag)
+	}
+
+	if _, err := client.GetIfNoneMatch(ctx, "missing", obj.Etag); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("GetIfNoneMatch() of a missing key error = %v, want ErrObjectNotFound", err)
+	}
+}
+
+func TestClient_PutIfAbsent_multipart(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "big.bin", []byte("existing"))
+
+	_, err := client.PutIfAbsent(ctx, &Object{
+		Key:  "big.bin",
+		Body: io.NopCloser(io.LimitReader(zeroReader{}, MinPartSize+1)),
+	}, WithPartSize(MinPartSize))
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("PutIfAbsent() multipart over an existing key error = %v, want ErrPreconditionFailed", err)
+	}
+}
+
+func TestClient_PutIfMatch_multipart(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	cur, err := client.Put(ctx, &Object{Key: "stream.log", Size: 3, Body: seekableBody("old")})
+	if err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	// Bodies of unknown size that don't fit in one part are uploaded in parts, and
+	// the condition is checked when the upload is completed.
+	stream := func() *Object {
+		return &Object{Key: "stream.log", Body: io.NopCloser(io.LimitReader(zeroReader{}, MinPartSize+1))}
+	}
+
+	if _, err := client.PutIfMatch(ctx, stream(), `"stale"`, WithPartSize(MinPartSize)); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("PutIfMatch() multipart with a stale ETag error = %v, want ErrPreconditionFailed", err)
+	}
+
+	obj, err := client.PutIfMatch(ctx, stream(), cur.Etag, WithPartSize(MinPartSize))
+	if err != nil {
+		t.Fatalf("PutIfMatch() multipart with the current ETag failed: %v", err)
+	}
+	if obj.Size != MinPartSize+1 {
+		t.Errorf("PutIfMatch() size = %d, want %d", obj.Size, MinPartSize+1)
+	}
+}
+
+// zeroReader is an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
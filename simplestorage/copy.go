@@ -0,0 +1,122 @@
+package simplestorage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+// Copy copies the object at srcKey to dstKey on the server side, without downloading
+// it.
+//
+// The copy is written to the Client's bucket (see For and OverrideBucket). Use
+// WithSourceBucket to copy from another bucket, and WithSourceVersion or WithSnapshot
+// to copy an older version of the source object.
+//
+// By default the copy keeps the source object's content headers and metadata. With
+// WithMetadataDirective(types.MetadataDirectiveReplace), the ones set with
+// WithContentType, WithContentDisposition, WithCacheControl and WithMetadata replace
+// them instead; every standard header that is not set, including Content-Encoding,
+// Content-Language and Expires, is carried over from the source object. With
+// WithNoOverwrite, an existing object at dstKey is left alone and Copy returns a
+// *ConditionError matching ErrPreconditionFailed.
+//
+// The returned Object describes the copy and has no Body.
+func (c *Client) Copy(ctx context.Context, srcKey, dstKey string, opts ...ClientOption) (*Object, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	srcBucket := o.BucketName
+	if o.SourceBucket != "" {
+		srcBucket = o.SourceBucket
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(o.BucketName),
+		Key:               aws.String(dstKey),
+		CopySource:        aws.String(copySource(srcBucket, srcKey, o.SourceVersion)),
+		MetadataDirective: cmp.Or(o.MetadataDirective, types.MetadataDirectiveCopy),
+	}
+
+	switch input.MetadataDirective {
+	case types.MetadataDirectiveCopy:
+		if o.ContentType != nil || o.ContentDisposition != nil || o.CacheControl != nil || o.Metadata != nil {
+			return nil, fmt.Errorf("simplestorage: can't copy %s/%s to %s/%s: new content headers or metadata need WithMetadataDirective(types.MetadataDirectiveReplace)", srcBucket, srcKey, o.BucketName, dstKey)
+		}
+
+	case types.MetadataDirectiveReplace:
+		src, err := c.headSource(ctx, srcBucket, srcKey, o)
+		if err != nil {
+			return nil, fmt.Errorf("simplestorage: can't copy %s/%s to %s/%s: %w", srcBucket, srcKey, o.BucketName, dstKey, err)
+		}
+
+		input.ContentType = cmp.Or(o.ContentType, src.ContentType)
+		input.ContentDisposition = cmp.Or(o.ContentDisposition, src.ContentDisposition)
+		input.CacheControl = cmp.Or(o.CacheControl, src.CacheControl)
+		input.ContentEncoding = src.ContentEncoding
+		input.ContentLanguage = src.ContentLanguage
+		if expires, err := http.ParseTime(lower(src.ExpiresString, "")); err == nil {
+			input.Expires = &expires
+		}
+		input.Metadata = o.Metadata
+		if input.Metadata == nil {
+			input.Metadata = maps.Clone(src.Metadata)
+		}
+
+	default:
+		return nil, fmt.Errorf("simplestorage: can't copy %s/%s to %s/%s: unknown metadata directive %q", srcBucket, srcKey, o.BucketName, dstKey, input.MetadataDirective)
+	}
+
+	s3Opts := o.readOptions()
+	if o.NoOverwrite {
+		s3Opts = append(slices.Clip(s3Opts), tigrisheaders.WithCreateObjectIfNotExists())
+	}
+
+	resp, err := c.cli.CopyObject(ctx, input, s3Opts...)
+	if err != nil {
+		if o.NoOverwrite && errors.Is(err, ErrPreconditionFailed) {
+			err = c.conditionFailed(ctx, dstKey, err, opts)
+		}
+		return nil, fmt.Errorf("simplestorage: can't copy %s/%s to %s/%s: %w", srcBucket, srcKey, o.BucketName, dstKey, err)
+	}
+
+	obj := &Object{
+		Bucket:             o.BucketName,
+		Key:                dstKey,
+		ContentType:        lower(input.ContentType, ""),
+		ContentDisposition: lower(input.ContentDisposition, ""),
+		Version:            lower(resp.VersionId, ""),
+		Metadata:           input.Metadata,
+	}
+	if resp.CopyObjectResult != nil {
+		obj.Etag = lower(resp.CopyObjectResult.ETag, "")
+		obj.LastModified = lower(resp.CopyObjectResult.LastModified, obj.LastModified)
+	}
+
+	return obj, nil
+}
+
+// headSource fetches the headers of the source object of a copy.
+func (c *Client) headSource(ctx context.Context, bucket, key string, o ClientOptions) (*s3.HeadObjectOutput, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if o.SourceVersion != "" {
+		input.VersionId = aws.String(o.SourceVersion)
+	}
+
+	return c.cli.HeadObject(ctx, input, o.readOptions()...)
+}
@@ -0,0 +1,31 @@
+package simplestorage_test
+
+import (
+	"context"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_Copy() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Copy into the backups bucket from the default bucket, as it was in a snapshot
+	_, err = client.For("my-backups").Copy(ctx, "db/dump.sql", "2025-01-01/dump.sql",
+		simplestorage.WithSourceBucket("my-default-bucket"),
+		simplestorage.WithSnapshot("1751631910169675092"),
+		simplestorage.WithMetadataDirective(types.MetadataDirectiveReplace),
+		simplestorage.WithMetadata(map[string]string{"source": "nightly"}),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+}
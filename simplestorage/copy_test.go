@@ -0,0 +1,253 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestClient_Copy(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.cli.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:             aws.String("test-bucket"),
+		Key:                aws.String("src.txt"),
+		Body:               strings.NewReader("hello"),
+		ContentType:        aws.String("text/plain"),
+		ContentDisposition: aws.String("inline"),
+		Metadata:           map[string]string{"owner": "alice"},
+	}); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		opts            []ClientOption
+		wantType        string
+		wantDisposition string
+		wantMetadata    map[string]string
+	}{
+		{
+			name:            "copy metadata",
+			wantType:        "text/plain",
+			wantDisposition: "inline",
+			wantMetadata:    map[string]string{"owner": "alice"},
+		},
+		{
+			name:            "replace without changes",
+			opts:            []ClientOption{WithMetadataDirective(types.MetadataDirectiveReplace)},
+			wantType:        "text/plain",
+			wantDisposition: "inline",
+			wantMetadata:    map[string]string{"owner": "alice"},
+		},
+		{
+			name:            "new content type",
+			opts:            []ClientOption{WithMetadataDirective(types.MetadataDirectiveReplace), WithContentType("text/markdown")},
+			wantType:        "text/markdown",
+			wantDisposition: "inline",
+			wantMetadata:    map[string]string{"owner": "alice"},
+		},
+		{
+			name:            "new metadata",
+			opts:            []ClientOption{WithMetadataDirective(types.MetadataDirectiveReplace), WithMetadata(map[string]string{"owner": "bob", "reviewed": "yes"})},
+			wantType:        "text/plain",
+			wantDisposition: "inline",
+			wantMetadata:    map[string]string{"owner": "bob", "reviewed": "yes"},
+		},
+		{
+			name:            "cleared metadata",
+			opts:            []ClientOption{WithMetadataDirective(types.MetadataDirectiveReplace), WithMetadata(map[string]string{}), WithContentDisposition("attachment")},
+			wantType:        "text/plain",
+			wantDisposition: "attachment",
+			wantMetadata:    map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := client.Copy(ctx, "src.txt", "dst.txt", tt.opts...)
+			if err != nil {
+				t.Fatalf("Copy() failed: %v", err)
+			}
+			if obj.Key != "dst.txt" || obj.Etag == "" {
+				t.Errorf("Copy() = key %q ETag %q, want key dst.txt and an ETag", obj.Key, obj.Etag)
+			}
+
+			got, err := client.Head(ctx, "dst.txt")
+			if err != nil {
+				t.Fatalf("Head() failed: %v", err)
+			}
+			if got.ContentType != tt.wantType || got.ContentDisposition != tt.wantDisposition {
+				t.Errorf("copy has type %q disposition %q, want %q %q", got.ContentType, got.ContentDisposition, tt.wantType, tt.wantDisposition)
+			}
+			if !maps.Equal(got.Metadata, tt.wantMetadata) {
+				t.Errorf("copy has metadata %v, want %v", got.Metadata, tt.wantMetadata)
+			}
+			if data := readTestObject(t, client, "dst.txt"); data != "hello" {
+				t.Errorf("copy contents = %q, want hello", data)
+			}
+		})
+	}
+
+	t.Run("no overwrite", func(t *testing.T) {
+		_, err := client.Copy(ctx, "src.txt", "dst.txt", WithNoOverwrite())
+		var ce *ConditionError
+		if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+			t.Fatalf("Copy() onto an existing object error = %v, want *ConditionError", err)
+		}
+		if ce.Etag == "" {
+			t.Error("ConditionError.Etag is empty, want the destination's ETag")
+		}
+
+		if _, err := client.Copy(ctx, "src.txt", "fresh.txt", WithNoOverwrite()); err != nil {
+			t.Errorf("Copy() to a new key with WithNoOverwrite() failed: %v", err)
+		}
+	})
+
+	t.Run("new headers without replace", func(t *testing.T) {
+		for _, opt := range []ClientOption{WithContentType("text/markdown"), WithMetadata(map[string]string{})} {
+			if _, err := client.Copy(ctx, "src.txt", "dst.txt", opt); err == nil || !strings.Contains(err.Error(), "MetadataDirectiveReplace") {
+				t.Errorf("Copy() error = %v, want one asking for MetadataDirectiveReplace", err)
+			}
+		}
+		if _, err := client.Copy(ctx, "src.txt", "dst.txt", WithMetadataDirective("MERGE")); err == nil {
+			t.Error("Copy() with an unknown metadata directive succeeded, want an error")
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		if _, err := client.Copy(ctx, "missing.txt", "dst.txt"); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Copy() error = %v, want ErrObjectNotFound", err)
+		}
+		if _, err := client.Copy(ctx, "missing.txt", "dst.txt", WithMetadataDirective(types.MetadataDirectiveReplace), WithContentType("text/plain")); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Copy() with new content type error = %v, want ErrObjectNotFound", err)
+		}
+	})
+}
+
+func TestClient_Copy_replaceKeepsHeaders(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := client.cli.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String("test-bucket"),
+		Key:             aws.String("page.html.gz"),
+		Body:            strings.NewReader("\x1f\x8b compressed"),
+		ContentType:     aws.String("text/html"),
+		CacheControl:    aws.String("max-age=60"),
+		ContentEncoding: aws.String("gzip"),
+		ContentLanguage: aws.String("de"),
+		Expires:         &expires,
+	}); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+
+	if _, err := client.Copy(ctx, "page.html.gz", "copy.html.gz",
+		WithMetadataDirective(types.MetadataDirectiveReplace),
+		WithMetadata(map[string]string{"reviewed": "yes"}),
+	); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+
+	got, err := client.cli.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String("test-bucket"),
+		Key:    aws.String("copy.html.gz"),
+	})
+	if err != nil {
+		t.Fatalf("HeadObject() failed: %v", err)
+	}
+
+	want := map[string]string{
+		"Content-Type":     "text/html",
+		"Cache-Control":    "max-age=60",
+		"Content-Encoding": "gzip",
+		"Content-Language": "de",
+		"Expires":          expires.Format(http.TimeFormat),
+	}
+	for name, value := range map[string]*string{
+		"Content-Type":     got.ContentType,
+		"Cache-Control":    got.CacheControl,
+		"Content-Encoding": got.ContentEncoding,
+		"Content-Language": got.ContentLanguage,
+		"Expires":          got.ExpiresString,
+	} {
+		if aws.ToString(value) != want[name] {
+			t.Errorf("copy has %s %q, want %q", name, aws.ToString(value), want[name])
+		}
+	}
+	if got.Metadata["reviewed"] != "yes" {
+		t.Errorf("copy has metadata %v, want reviewed=yes", got.Metadata)
+	}
+}
+
+func TestClient_Copy_crossBucket(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "report.csv", []byte("a,b,c"))
+
+	if _, err := client.CreateBucket(ctx, "backups"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	obj, err := client.For("backups").Copy(ctx, "report.csv", "2024/report.csv", WithSourceBucket("test-bucket"))
+	if err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+	if obj.Bucket != "backups" {
+		t.Errorf("Copy() bucket = %q, want backups", obj.Bucket)
+	}
+	if data := readTestObject(t, client.For("backups"), "2024/report.csv"); data != "a,b,c" {
+		t.Errorf("copy contents = %q, want a,b,c", data)
+	}
+
+	if _, err := client.Copy(ctx, "2024/report.csv", "restored.csv", WithSourceBucket("backups")); err != nil {
+		t.Fatalf("Copy() back failed: %v", err)
+	}
+	if data := readTestObject(t, client, "restored.csv"); data != "a,b,c" {
+		t.Errorf("restored contents = %q, want a,b,c", data)
+	}
+}
+
+func TestClient_Copy_fromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "versioned", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	bucket := client.For("versioned")
+	putTestObject(t, bucket, "config.json", []byte(`{"v":1}`))
+
+	snap, err := client.CreateBucketSnapshot(ctx, "versioned", "v1")
+	if err != nil {
+		t.Fatalf("CreateBucketSnapshot() failed: %v", err)
+	}
+
+	putTestObject(t, bucket, "config.json", []byte(`{"v":2}`))
+
+	old, err := bucket.Get(ctx, "config.json", WithSnapshot(snap.Version))
+	if err != nil {
+		t.Fatalf("Get() from snapshot failed: %v", err)
+	}
+	old.Body.Close()
+	if old.Size != int64(len(`{"v":1}`)) {
+		t.Errorf("Get() from snapshot size = %d, want the snapshot's", old.Size)
+	}
+
+	if _, err := bucket.Copy(ctx, "config.json", "config.json", WithSnapshot(snap.Version)); err != nil {
+		t.Fatalf("Copy() from snapshot failed: %v", err)
+	}
+	if data := readTestObject(t, bucket, "config.json"); data != `{"v":1}` {
+		t.Errorf("restored contents = %q, want the snapshot's", data)
+	}
+}
@@ -0,0 +1,227 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	storage "github.com/tigrisdata/storage-go"
+)
+
+// MaxDeleteBatch is the most keys a single DeleteObjects request can remove.
+const MaxDeleteBatch = 1000
+
+// DeleteReport describes the outcome of DeleteMany or DeletePrefix.
+type DeleteReport struct {
+	Deleted []string    // Keys that were deleted, or would be in a dry run, sorted
+	Failed  []*KeyError // Keys that could not be deleted, sorted by key
+}
+
+// DeleteMany deletes the objects at keys using DeleteObjects requests of up to
+// MaxDeleteBatch keys each, with up to the configured number of requests in flight
+// (see WithConcurrency). Deleting a key that does not exist is not an error.
+//
+// A failure to delete some keys does not stop the others: the report lists every
+// deleted and failed key, and the returned error joins the failures. With WithDryRun,
+// nothing is deleted and the report lists the keys that would be.
+func (c *Client) DeleteMany(ctx context.Context, keys []string, opts ...ClientOption) (*DeleteReport, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	seq := func(yield func(types.ObjectIdentifier, error) bool) {
+		for _, key := range keys {
+			if !yield(types.ObjectIdentifier{Key: aws.String(key)}, nil) {
+				return
+			}
+		}
+	}
+
+	report, failed, err := c.deleteObjects(ctx, seq, o, nil)
+	if err := errors.Join(err, failed); err != nil {
+		return report, fmt.Errorf("simplestorage: can't delete objects in %s: %w", o.BucketName, err)
+	}
+
+	return report, nil
+}
+
+// DeletePrefix deletes every object whose key starts with prefix, like removing a
+// directory. Keys are deleted in batches as the listing is read, as in DeleteMany.
+//
+// With WithDryRun, nothing is deleted and the report lists the keys that would be. If
+// the listing fails or ctx is cancelled, DeletePrefix returns the keys deleted so far
+// and the error, joined with the failures of the keys it did try to delete.
+func (c *Client) DeletePrefix(ctx context.Context, prefix string, opts ...ClientOption) (*DeleteReport, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	report, failed, err := c.deleteObjects(ctx, c.listIdentifiers(ctx, append(slices.Clip(opts), WithPrefix(prefix))), o, nil)
+	if err := errors.Join(err, failed); err != nil {
+		return report, fmt.Errorf("simplestorage: can't delete %s/%s: %w", o.BucketName, prefix, err)
+	}
+
+	return report, nil
+}
+
+// listIdentifiers returns an iterator over the identifiers of the objects listed with
+// opts, for deleting them.
+func (c *Client) listIdentifiers(ctx context.Context, opts []ClientOption) iter.Seq2[types.ObjectIdentifier, error] {
+	return func(yield func(types.ObjectIdentifier, error) bool) {
+		for obj, err := range c.ListAll(ctx, opts...) {
+			if !yield(types.ObjectIdentifier{Key: aws.String(obj.Key)}, err) {
+				return
+			}
+		}
+	}
+}
+
+// deleteObjects deletes the objects yielded by seq in concurrent batches and reports
+// the result. If progress is not nil, it is called with the number of objects deleted
+// after each batch; calls don't overlap.
+//
+// It stops at the first error from seq or when ctx is cancelled, and returns that error
+// as err. The keys that could not be deleted are joined into failed, so a listing that
+// stopped early is never hidden behind per-key failures.
+func (c *Client) deleteObjects(ctx context.Context, seq iter.Seq2[types.ObjectIdentifier, error], o ClientOptions, progress func(deleted int)) (report *DeleteReport, failed, err error) {
+	report = &DeleteReport{}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		listErr error
+		total   int
+	)
+	sem := make(chan struct{}, max(o.Concurrency, 1))
+
+	flush := func(batch []types.ObjectIdentifier) {
+		if o.DryRun {
+			for _, id := range batch {
+				report.Deleted = append(report.Deleted, aws.ToString(id.Key))
+			}
+			return
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			deleted, failed := c.deleteBatch(ctx, batch, o)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Deleted = append(report.Deleted, deleted...)
+			report.Failed = append(report.Failed, failed...)
+			if progress != nil {
+				progress(len(deleted))
+			}
+		})
+	}
+
+	batch := make([]types.ObjectIdentifier, 0, MaxDeleteBatch)
+	for id, err := range seq {
+		if err != nil {
+			listErr = err
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		total++
+		batch = append(batch, id)
+		if len(batch) == MaxDeleteBatch {
+			flush(batch)
+			batch = make([]types.ObjectIdentifier, 0, MaxDeleteBatch)
+		}
+	}
+	if len(batch) > 0 && listErr == nil && ctx.Err() == nil {
+		flush(batch)
+	}
+
+	wg.Wait()
+
+	slices.Sort(report.Deleted)
+	slices.SortFunc(report.Failed, func(a, b *KeyError) int { return strings.Compare(a.Key, b.Key) })
+
+	if len(report.Failed) > 0 {
+		errs := make([]error, len(report.Failed))
+		for i, ke := range report.Failed {
+			errs[i] = ke
+		}
+		failed = fmt.Errorf("%d of %d objects failed: %w", len(report.Failed), total, errors.Join(errs...))
+	}
+
+	if listErr != nil {
+		return report, failed, listErr
+	}
+
+	return report, failed, ctx.Err()
+}
+
+// deleteBatch deletes up to MaxDeleteBatch objects with one DeleteObjects request. If
+// the whole request fails, every object is reported as failed with its error.
+func (c *Client) deleteBatch(ctx context.Context, objects []types.ObjectIdentifier, o ClientOptions) (deleted []string, failed []*KeyError) {
+	resp, err := c.cli.DeleteObjects(
+		ctx,
+		&s3.DeleteObjectsInput{
+			Bucket: aws.String(o.BucketName),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		},
+		o.S3Options...,
+	)
+	if err != nil {
+		for _, id := range objects {
+			failed = append(failed, &KeyError{Key: aws.ToString(id.Key), Err: err})
+		}
+		return nil, failed
+	}
+
+	return deleteResults(objects, resp.Errors)
+}
+
+// deleteResults splits the objects of a quiet DeleteObjects request into the keys of
+// the deleted and failed ones. Per-key errors are wrapped as *storage.Error, so they
+// match the sentinel errors for their codes.
+func deleteResults(objects []types.ObjectIdentifier, errs []types.Error) (deleted []string, failed []*KeyError) {
+	failedIDs := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		key := aws.ToString(e.Key)
+		failedIDs[key+"\x00"+aws.ToString(e.VersionId)] = true
+		failed = append(failed, &KeyError{
+			Key: key,
+			Err: storage.WrapError("DeleteObjects", &smithy.GenericAPIError{
+				Code:    aws.ToString(e.Code),
+				Message: aws.ToString(e.Message),
+			}),
+		})
+	}
+
+	for _, id := range objects {
+		if !failedIDs[aws.ToString(id.Key)+"\x00"+aws.ToString(id.VersionId)] {
+			deleted = append(deleted, aws.ToString(id.Key))
+		}
+	}
+
+	return deleted, failed
+}
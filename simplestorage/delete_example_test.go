@@ -0,0 +1,35 @@
+package simplestorage_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_DeletePrefix() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// See what would be removed first
+	plan, err := client.DeletePrefix(ctx, "tmp/", simplestorage.WithDryRun())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Would delete", len(plan.Deleted), "objects")
+
+	report, err := client.DeletePrefix(ctx, "tmp/")
+	for _, failed := range report.Failed {
+		fmt.Println("Could not delete", failed.Key, failed.Err)
+	}
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+}
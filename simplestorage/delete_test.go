@@ -0,0 +1,132 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestClient_DeleteMany(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	// More than two batches' worth
+	var keys []string
+	for i := range 2*MaxDeleteBatch + 5 {
+		key := fmt.Sprintf("tmp/%05d", i)
+		putTestObject(t, client, key, nil)
+		keys = append(keys, key)
+	}
+	putTestObject(t, client, "keep", []byte("x"))
+
+	report, err := client.DeleteMany(ctx, append(keys, "tmp/missing"), WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("DeleteMany() failed: %v", err)
+	}
+	if len(report.Deleted) != len(keys)+1 || len(report.Failed) != 0 {
+		t.Errorf("DeleteMany() deleted %d and failed %d keys, want %d and 0", len(report.Deleted), len(report.Failed), len(keys)+1)
+	}
+	if !slices.IsSorted(report.Deleted) {
+		t.Error("DeleteMany() report is not sorted")
+	}
+	if got := listKeys(t, client); !slices.Equal(got, []string{"keep"}) {
+		t.Errorf("keys after DeleteMany() = %q, want only keep", got)
+	}
+}
+
+func TestClient_DeletePrefix(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		opts        []ClientOption
+		wantDeleted []string
+		wantKeys    []string
+	}{
+		{
+			name:        "delete directory",
+			prefix:      "logs/",
+			wantDeleted: []string{"logs/a", "logs/b/c"},
+			wantKeys:    []string{"logsx", "other/d"},
+		},
+		{
+			name:        "dry run",
+			prefix:      "logs/",
+			opts:        []ClientOption{WithDryRun()},
+			wantDeleted: []string{"logs/a", "logs/b/c"},
+			wantKeys:    []string{"logs/a", "logs/b/c", "logsx", "other/d"},
+		},
+		{
+			name:     "nothing matches",
+			prefix:   "nothing/",
+			wantKeys: []string{"logs/a", "logs/b/c", "logsx", "other/d"},
+		},
+		{
+			name:        "small pages",
+			prefix:      "",
+			opts:        []ClientOption{WithMaxKeys(1)},
+			wantDeleted: []string{"logs/a", "logs/b/c", "logsx", "other/d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newTestClient(t)
+			for _, key := range []string{"logs/a", "logs/b/c", "logsx", "other/d"} {
+				putTestObject(t, client, key, []byte(key))
+			}
+
+			report, err := client.DeletePrefix(ctx, tt.prefix, tt.opts...)
+			if err != nil {
+				t.Fatalf("DeletePrefix() failed: %v", err)
+			}
+			if !slices.Equal(report.Deleted, tt.wantDeleted) {
+				t.Errorf("Deleted = %q, want %q", report.Deleted, tt.wantDeleted)
+			}
+			if got := listKeys(t, client); !slices.Equal(got, tt.wantKeys) {
+				t.Errorf("keys after DeletePrefix() = %q, want %q", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestClient_DeletePrefix_missingBucket(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := client.DeletePrefix(context.Background(), "x/", OverrideBucket("missing")); !errors.Is(err, ErrBucketNotFound) {
+		t.Errorf("DeletePrefix() error = %v, want ErrBucketNotFound", err)
+	}
+}
+
+func TestDeleteResults(t *testing.T) {
+	objects := []types.ObjectIdentifier{
+		{Key: aws.String("a")},
+		{Key: aws.String("b")},
+		{Key: aws.String("c"), VersionId: aws.String("v1")},
+		{Key: aws.String("c"), VersionId: aws.String("v2")},
+	}
+	deleted, failed := deleteResults(objects, []types.Error{{
+		Key:     aws.String("b"),
+		Code:    aws.String("AccessDenied"),
+		Message: aws.String("Access Denied"),
+	}, {
+		Key:       aws.String("c"),
+		VersionId: aws.String("v2"),
+		Code:      aws.String("InternalError"),
+	}})
+
+	if !slices.Equal(deleted, []string{"a", "c"}) {
+		t.Errorf("deleted = %q, want [a c]", deleted)
+	}
+	if len(failed) != 2 || failed[0].Key != "b" || failed[1].Key != "c" {
+		t.Fatalf("failed = %v, want keys b and c", failed)
+	}
+	if !errors.Is(failed[0], ErrAccessDenied) {
+		t.Errorf("failure %v does not match ErrAccessDenied", failed[0])
+	}
+}
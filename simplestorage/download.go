@@ -0,0 +1,137 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ErrObjectChanged is returned when an object is overwritten while it is being read
+// in several requests, so the parts read so far no longer belong to the same object.
+var ErrObjectChanged = errors.New("simplestorage: object changed while it was being read")
+
+// Download fetches an object into w by reading byte ranges in parallel. It is faster
+// than Get for large objects because each range uses its own connection.
+//
+// Download first looks up the object's size and ETag, then fetches ranges of the
+// part size (see WithPartSize) with up to the configured number of concurrent
+// requests (see WithConcurrency). Each range is written into w at its offset, so
+// writes may arrive out of order. Every range request is conditional on the ETag
+// seen at the start; if the object is overwritten mid-download, Download returns an
+// error wrapping ErrObjectChanged and the contents of w are incomplete.
+//
+// The returned Object describes what was downloaded and has no Body.
+func (c *Client) Download(ctx context.Context, key string, w io.WriterAt, opts ...ClientOption) (*Object, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	obj, err := c.headObject(ctx, key, o)
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't download %s/%s: %w", o.BucketName, key, err)
+	}
+
+	partSize := o.PartSize
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(o.Concurrency, 1))
+
+	for start := int64(0); start < obj.Size; start += partSize {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		end := min(start+partSize, obj.Size) - 1
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.downloadRange(ctx, key, obj.Etag, start, end, w, o); err != nil {
+				cancel(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, fmt.Errorf("simplestorage: can't download %s/%s: %w", o.BucketName, key, err)
+	}
+
+	return obj, nil
+}
+
+// downloadRange fetches the inclusive byte range [start, end] of key and writes it
+// into w at offset start. The request only succeeds if the object still has etag.
+func (c *Client) downloadRange(ctx context.Context, key, etag string, start, end int64, w io.WriterAt, o ClientOptions) error {
+	body, err := c.getRange(ctx, key, etag, start, end, o)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	n, err := io.Copy(io.NewOffsetWriter(w, start), body)
+	if err != nil {
+		return fmt.Errorf("reading bytes %d-%d: %w", start, end, err)
+	}
+	if want := end - start + 1; n != want {
+		return fmt.Errorf("reading bytes %d-%d: got %d bytes, want %d: %w", start, end, n, want, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// getRange starts a GET of the inclusive byte range [start, end] of key, or of
+// everything from start onwards if end is negative. If etag is set, the request only
+// succeeds if the object still has that ETag; otherwise the error wraps
+// ErrObjectChanged.
+func (c *Client) getRange(ctx context.Context, key, etag string, start, end int64, o ClientOptions) (io.ReadCloser, error) {
+	rng := fmt.Sprintf("bytes=%d-", start)
+	if end >= 0 {
+		rng += strconv.FormatInt(end, 10)
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(o.BucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(rng),
+	}
+	if etag != "" {
+		input.IfMatch = aws.String(etag)
+	}
+
+	resp, err := c.cli.GetObject(ctx, input, o.readOptions()...)
+	if err != nil {
+		if errors.Is(err, ErrPreconditionFailed) {
+			return nil, fmt.Errorf("reading %s: %w", rng, ErrObjectChanged)
+		}
+		return nil, fmt.Errorf("reading %s: %w", rng, err)
+	}
+
+	if got := lower(resp.ETag, ""); etag != "" && got != etag {
+		resp.Body.Close()
+		return nil, fmt.Errorf("reading %s: got ETag %s, want %s: %w", rng, got, etag, ErrObjectChanged)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,143 @@
+package simplestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// hookWriterAt is an in-memory io.WriterAt that calls onWrite before the first write.
+type hookWriterAt struct {
+	mu      sync.Mutex
+	buf     []byte
+	onWrite func()
+}
+
+func (w *hookWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.onWrite != nil {
+		w.onWrite()
+		w.onWrite = nil
+	}
+	if end := off + int64(len(p)); end > int64(len(w.buf)) {
+		w.buf = append(w.buf, make([]byte, end-int64(len(w.buf)))...)
+	}
+	copy(w.buf[off:], p)
+	return len(p), nil
+}
+
+func TestDownload(t *testing.T) {
+	payload := bytes.Repeat([]byte("tigris!"), 10000)
+
+	tests := []struct {
+		name string
+		body []byte
+		opts []ClientOption
+	}{
+		{
+			name: "single range",
+			body: payload,
+		},
+		{
+			name: "many ranges",
+			body: payload,
+			opts: []ClientOption{WithPartSize(4096), WithConcurrency(3)},
+		},
+		{
+			name: "part size divides object",
+			body: payload[:8192],
+			opts: []ClientOption{WithPartSize(1024)},
+		},
+		{
+			name: "empty object",
+			body: []byte{},
+			opts: []ClientOption{WithPartSize(1024)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newTestClient(t)
+
+			if _, err := client.Put(ctx, &Object{
+				Key:  "weights.bin",
+				Size: int64(len(tt.body)),
+				Body: seekableBody(string(tt.body)),
+			}); err != nil {
+				t.Fatalf("Put() failed: %v", err)
+			}
+
+			fout, err := os.Create(filepath.Join(t.TempDir(), "weights.bin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer fout.Close()
+
+			obj, err := client.Download(ctx, "weights.bin", fout, tt.opts...)
+			if err != nil {
+				t.Fatalf("Download() failed: %v", err)
+			}
+			if obj.Size != int64(len(tt.body)) {
+				t.Errorf("Download() size = %d, want %d", obj.Size, len(tt.body))
+			}
+			if obj.Etag == "" {
+				t.Error("Download() returned no ETag")
+			}
+
+			got, err := os.ReadFile(fout.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.body) {
+				t.Errorf("Download() wrote %d bytes, want %d matching bytes", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestDownload_objectChanged(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	payload := bytes.Repeat([]byte("a"), 4096)
+	if _, err := client.Put(ctx, &Object{
+		Key:  "weights.bin",
+		Size: int64(len(payload)),
+		Body: seekableBody(string(payload)),
+	}); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	w := &hookWriterAt{
+		onWrite: func() {
+			// Overwrite the object after the first range arrives.
+			if _, err := client.Put(ctx, &Object{
+				Key:  "weights.bin",
+				Size: 4,
+				Body: seekableBody("bbbb"),
+			}); err != nil {
+				t.Errorf("Put() failed: %v", err)
+			}
+		},
+	}
+
+	_, err := client.Download(ctx, "weights.bin", w, WithPartSize(1024), WithConcurrency(1))
+	if !errors.Is(err, ErrObjectChanged) {
+		t.Errorf("Download() error = %v, want ErrObjectChanged", err)
+	}
+}
+
+func TestDownload_notFound(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := client.Download(context.Background(), "missing", &hookWriterAt{}); err == nil {
+		t.Error("Download() of a missing object succeeded, want error")
+	}
+}
@@ -0,0 +1,222 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	storage "github.com/tigrisdata/storage-go"
+)
+
+// ErrBucketIsForkParent is returned by EmptyBucket and forced DeleteBucket calls on a
+// bucket that other buckets were forked from, unless WithAllowForkParent() is used.
+var ErrBucketIsForkParent = errors.New("simplestorage: bucket has forks, use WithAllowForkParent() to empty it anyway")
+
+// EmptyProgress counts what EmptyBucket has removed so far.
+type EmptyProgress struct {
+	Uploads  int // In-progress multipart uploads aborted
+	Versions int // Object versions and delete markers deleted
+	Objects  int // Current objects deleted
+}
+
+// EmptyBucket removes everything from a bucket so it can be deleted: in-progress
+// multipart uploads are aborted, then every object version and delete marker and
+// every remaining object is deleted, with several requests in flight at once. Use
+// WithProgress() to follow along.
+//
+// EmptyBucket refuses to empty a bucket that has forks and returns
+// ErrBucketIsForkParent, unless WithAllowForkParent() is used. A failure to remove
+// some objects does not stop the others; the returned error joins the failures.
+func (c *Client) EmptyBucket(ctx context.Context, bucket string, opts ...BucketOption) (*EmptyProgress, error) {
+	if bucket == "" {
+		return nil, ErrBucketNameRequired
+	}
+
+	o := new(BucketOptions).defaults()
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	progress, err := c.emptyBucket(ctx, bucket, o)
+	if err != nil {
+		return progress, fmt.Errorf("simplestorage: can't empty bucket %s: %w", bucket, err)
+	}
+
+	return progress, nil
+}
+
+// emptyBucket implements EmptyBucket, returning errors unwrapped.
+func (c *Client) emptyBucket(ctx context.Context, bucket string, o BucketOptions) (*EmptyProgress, error) {
+	// Fail closed: unlike GetBucketInfo, don't fall back to assuming the bucket has
+	// no forks when its fork metadata can't be read.
+	if !o.AllowForkParent {
+		info, err := c.cli.HeadBucketForkOrSnapshot(ctx, &s3.HeadBucketInput{
+			Bucket: aws.String(bucket),
+		}, o.S3Options...)
+		if err != nil {
+			return nil, fmt.Errorf("checking for forks: %w", err)
+		}
+		if info.IsForkParent {
+			return nil, ErrBucketIsForkParent
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		progress EmptyProgress
+	)
+	report := func(update func(*EmptyProgress)) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		update(&progress)
+		if o.Progress != nil {
+			o.Progress(progress)
+		}
+	}
+
+	var failed []error
+	removeErr := func() error {
+		if len(failed) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%d objects or uploads could not be removed: %w", len(failed), errors.Join(failed...))
+	}
+
+	failed = append(failed, c.abortUploads(ctx, bucket, o, func() {
+		report(func(p *EmptyProgress) { p.Uploads++ })
+	})...)
+	if err := ctx.Err(); err != nil {
+		return &progress, errors.Join(err, removeErr())
+	}
+
+	co := new(ClientOptions).defaults(c.options)
+	co.BucketName = bucket
+	co.S3Options = o.S3Options
+
+	versions, _, err := c.deleteObjects(ctx, c.listVersions(ctx, bucket, o), co, func(n int) {
+		report(func(p *EmptyProgress) { p.Versions += n })
+	})
+	for _, ke := range versions.Failed {
+		failed = append(failed, ke)
+	}
+	if err != nil && !isNotImplemented(err) {
+		return &progress, errors.Join(fmt.Errorf("listing object versions: %w", err), removeErr())
+	}
+
+	objects, _, err := c.deleteObjects(ctx, c.listIdentifiers(ctx, []ClientOption{OverrideBucket(bucket), WithS3Options(o.S3Options...)}), co, func(n int) {
+		report(func(p *EmptyProgress) { p.Objects += n })
+	})
+	for _, ke := range objects.Failed {
+		failed = append(failed, ke)
+	}
+	if err != nil {
+		return &progress, errors.Join(fmt.Errorf("listing objects: %w", err), removeErr())
+	}
+
+	return &progress, removeErr()
+}
+
+// abortUploads aborts every in-progress multipart upload in bucket, calling done after
+// each one. It returns the failures.
+func (c *Client) abortUploads(ctx context.Context, bucket string, o BucketOptions, done func()) []error {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed []error
+	)
+	sem := make(chan struct{}, DefaultConcurrency)
+
+	input := &s3.ListMultipartUploadsInput{Bucket: aws.String(bucket)}
+	for {
+		resp, err := c.cli.ListMultipartUploads(ctx, input, o.S3Options...)
+		if err != nil {
+			if !isNotImplemented(err) {
+				failed = append(failed, err)
+			}
+			break
+		}
+
+		for _, u := range resp.Uploads {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Go(func() {
+				defer func() { <-sem }()
+
+				_, err := c.cli.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+					Bucket:   aws.String(bucket),
+					Key:      u.Key,
+					UploadId: u.UploadId,
+				}, o.S3Options...)
+				if err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+					mu.Lock()
+					failed = append(failed, &KeyError{Key: aws.ToString(u.Key), Err: err})
+					mu.Unlock()
+					return
+				}
+				done()
+			})
+		}
+
+		if ctx.Err() != nil || !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+		input.KeyMarker = resp.NextKeyMarker
+		input.UploadIdMarker = resp.NextUploadIdMarker
+	}
+
+	wg.Wait()
+
+	return failed
+}
+
+// listVersions returns an iterator over the identifiers of every object version and
+// delete marker in bucket.
+func (c *Client) listVersions(ctx context.Context, bucket string, o BucketOptions) iter.Seq2[types.ObjectIdentifier, error] {
+	return func(yield func(types.ObjectIdentifier, error) bool) {
+		input := &s3.ListObjectVersionsInput{Bucket: aws.String(bucket)}
+
+		for {
+			resp, err := c.cli.ListObjectVersions(ctx, input, o.S3Options...)
+			if err != nil {
+				yield(types.ObjectIdentifier{}, err)
+				return
+			}
+
+			for _, v := range resp.Versions {
+				if !yield(types.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId}, nil) {
+					return
+				}
+			}
+			for _, m := range resp.DeleteMarkers {
+				if !yield(types.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId}, nil) {
+					return
+				}
+			}
+
+			if !aws.ToBool(resp.IsTruncated) {
+				return
+			}
+			input.KeyMarker = resp.NextKeyMarker
+			input.VersionIdMarker = resp.NextVersionIdMarker
+		}
+	}
+}
+
+// isNotImplemented reports whether err means the server does not support the call,
+// so EmptyBucket can skip that kind of cleanup.
+func isNotImplemented(err error) bool {
+	var tigrisErr *storage.Error
+	return errors.As(err, &tigrisErr) && tigrisErr.Code == "NotImplemented"
+}
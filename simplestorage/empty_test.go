@@ -0,0 +1,189 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	storage "github.com/tigrisdata/storage-go"
+)
+
+// withBucketS3Options passes opts to the S3 calls of a bucket operation.
+func withBucketS3Options(opts ...func(*s3.Options)) BucketOption {
+	return func(o *BucketOptions) {
+		o.S3Options = append(o.S3Options, opts...)
+	}
+}
+
+// failOperation makes calls of the S3 operation op fail with err once the first after
+// calls have gone through.
+func failOperation(op string, after int32, err error) func(*s3.Options) {
+	var calls atomic.Int32
+
+	return func(o *s3.Options) {
+		o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("failOperation"+op, func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				if awsmiddleware.GetOperationName(ctx) == op && calls.Add(1) > after {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, err
+				}
+				return next.HandleInitialize(ctx, in)
+			}), middleware.After)
+		})
+	}
+}
+
+func TestClient_DeleteBucket_force(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "doomed"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	bucket := client.For("doomed")
+
+	const objects = MaxDeleteBatch + 500
+	for i := range objects {
+		putTestObject(t, bucket, fmt.Sprintf("obj/%05d", i), []byte("x"))
+	}
+	if _, err := client.cli.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String("doomed"),
+		Key:    aws.String("unfinished"),
+	}); err != nil {
+		t.Fatalf("CreateMultipartUpload() failed: %v", err)
+	}
+
+	if err := client.DeleteBucket(ctx, "doomed"); !errors.Is(err, ErrBucketNotEmpty) {
+		t.Fatalf("DeleteBucket() without WithForce() error = %v, want ErrBucketNotEmpty", err)
+	}
+
+	var (
+		calls int
+		last  EmptyProgress
+	)
+	err := client.DeleteBucket(ctx, "doomed", WithForce(), WithProgress(func(p EmptyProgress) {
+		calls++
+		last = p
+	}))
+	if err != nil {
+		t.Fatalf("DeleteBucket() with WithForce() failed: %v", err)
+	}
+
+	if calls < 3 {
+		t.Errorf("progress reported %d times, want at least 3", calls)
+	}
+	if last.Uploads != 1 || last.Versions+last.Objects != objects {
+		t.Errorf("final progress = %+v, want 1 upload and %d objects", last, objects)
+	}
+	if _, err := client.GetBucketInfo(ctx, "doomed"); !errors.Is(err, ErrBucketNotFound) {
+		t.Errorf("GetBucketInfo() after DeleteBucket() error = %v, want ErrBucketNotFound", err)
+	}
+}
+
+func TestClient_EmptyBucket_forkParent(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "parent", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	putTestObject(t, client.For("parent"), "data", []byte("shared"))
+	if _, err := client.ForkBucket(ctx, "parent", "child"); err != nil {
+		t.Fatalf("ForkBucket() failed: %v", err)
+	}
+
+	if _, err := client.EmptyBucket(ctx, "parent"); !errors.Is(err, ErrBucketIsForkParent) {
+		t.Fatalf("EmptyBucket() of a fork parent error = %v, want ErrBucketIsForkParent", err)
+	}
+	if err := client.DeleteBucket(ctx, "parent", WithForce()); !errors.Is(err, ErrBucketIsForkParent) {
+		t.Fatalf("DeleteBucket() of a fork parent error = %v, want ErrBucketIsForkParent", err)
+	}
+	if got := listKeys(t, client.For("parent")); len(got) != 1 {
+		t.Errorf("parent has keys %q after refused EmptyBucket(), want them kept", got)
+	}
+
+	progress, err := client.EmptyBucket(ctx, "parent", WithAllowForkParent())
+	if err != nil {
+		t.Fatalf("EmptyBucket() with WithAllowForkParent() failed: %v", err)
+	}
+	if progress.Versions+progress.Objects != 1 {
+		t.Errorf("EmptyBucket() = %+v, want 1 object removed", progress)
+	}
+	if got := listKeys(t, client.For("parent")); len(got) != 0 {
+		t.Errorf("parent has keys %q after EmptyBucket(), want none", got)
+	}
+	if got := listKeys(t, client.For("child")); len(got) != 1 {
+		t.Errorf("fork has keys %q, want its own copy kept", got)
+	}
+}
+
+func TestClient_EmptyBucket_missing(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := client.EmptyBucket(context.Background(), "missing"); !errors.Is(err, ErrBucketNotFound) {
+		t.Errorf("EmptyBucket() error = %v, want ErrBucketNotFound", err)
+	}
+	if _, err := client.EmptyBucket(context.Background(), ""); !errors.Is(err, ErrBucketNameRequired) {
+		t.Errorf("EmptyBucket(\"\") error = %v, want ErrBucketNameRequired", err)
+	}
+}
+
+func TestClient_EmptyBucket_forkCheckFails(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "guarded"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	putTestObject(t, client.For("guarded"), "data", []byte("keep me"))
+
+	errDenied := errors.New("access denied")
+	failHead := withBucketS3Options(failOperation("HeadBucket", 0, errDenied))
+
+	if _, err := client.EmptyBucket(ctx, "guarded", failHead); !errors.Is(err, errDenied) {
+		t.Errorf("EmptyBucket() error = %v, want the HeadBucket error", err)
+	}
+	if err := client.DeleteBucket(ctx, "guarded", WithForce(), failHead); !errors.Is(err, errDenied) {
+		t.Errorf("DeleteBucket() error = %v, want the HeadBucket error", err)
+	}
+	if got := listKeys(t, client.For("guarded")); len(got) != 1 {
+		t.Errorf("bucket has keys %q after a failed fork check, want them kept", got)
+	}
+}
+
+func TestClient_EmptyBucket_listingFails(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "flaky"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	for i := range MaxDeleteBatch + 1 {
+		putTestObject(t, client.For("flaky"), fmt.Sprintf("obj/%05d", i), []byte("x"))
+	}
+
+	errList := errors.New("listing interrupted")
+	notImplemented := storage.WrapError("ListObjectVersions", &smithy.GenericAPIError{Code: "NotImplemented"})
+
+	// Versions can't be listed, the first batch of objects can't be deleted and the
+	// second page of the listing fails.
+	_, err := client.EmptyBucket(ctx, "flaky", withBucketS3Options(
+		failOperation("ListObjectVersions", 0, notImplemented),
+		failOperation("DeleteObjects", 0, errors.New("delete refused")),
+		failOperation("ListObjectsV2", 1, errList),
+	))
+
+	if !errors.Is(err, errList) {
+		t.Errorf("EmptyBucket() error = %v, want the listing error", err)
+	}
+	var ke *KeyError
+	if !errors.As(err, &ke) {
+		t.Errorf("EmptyBucket() error = %v, want the per-key failures too", err)
+	}
+}
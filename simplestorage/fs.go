@@ -0,0 +1,385 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// errIsDir is returned when reading a directory of an FS as a file.
+var errIsDir = errors.New("is a directory")
+
+// FS is a read-only view of the objects under a prefix of a bucket. It implements
+// fs.FS, fs.ReadDirFS, fs.ReadFileFS, fs.StatFS and fs.SubFS, so it works with
+// http.FS, template.ParseFS, fs.WalkDir and friends.
+//
+// Keys are split on "/" into directories. A directory exists as long as some key
+// starts with its name followed by a slash, so empty directories can't be
+// represented. Keys that are not valid fs paths, such as ones with empty, "." or ".."
+// elements, are skipped by ReadDir and can't be opened. If a key is both an object
+// and the prefix of other keys, the object wins.
+//
+// Files opened from an FS are backed by an ObjectReader, so they implement io.Seeker
+// and io.ReaderAt, and reads fail with ErrObjectChanged instead of mixing versions if
+// the object is overwritten while open. To get a consistent, immutable view of the
+// whole tree while the bucket changes, pin the FS to a snapshot with WithSnapshot.
+type FS struct {
+	c      *Client
+	ctx    context.Context
+	prefix string
+	opts   []ClientOption
+}
+
+// FS returns a read-only file system of the objects under prefix, which is treated as
+// a directory: "site" and "site/" both make "site/index.html" available as
+// "index.html". An empty prefix gives the whole bucket.
+//
+// The options apply to every call the file system makes; for example, use
+// OverrideBucket to read another bucket and WithSnapshot with a version from
+// CreateBucketSnapshot or ListBucketSnapshots to read the bucket as it was in that
+// snapshot. Requests use context.Background() unless set with FS.WithContext.
+func (c *Client) FS(prefix string, opts ...ClientOption) *FS {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &FS{
+		c:      c,
+		ctx:    context.Background(),
+		prefix: prefix,
+		opts:   opts,
+	}
+}
+
+// WithContext returns a copy of the file system whose requests use ctx. Files that
+// are already open keep using the context they were opened with.
+func (fsys *FS) WithContext(ctx context.Context) *FS {
+	cp := *fsys
+	cp.ctx = ctx
+	return &cp
+}
+
+// Open implements fs.FS. Opening a directory returns an fs.ReadDirFile.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name != "." {
+		r, err := fsys.c.Open(fsys.ctx, fsys.key(name), fsys.opts...)
+		if err == nil {
+			return &fsFile{ObjectReader: r, info: objectInfo(path.Base(name), r.Info())}, nil
+		}
+		if !errors.Is(err, ErrObjectNotFound) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+
+	info, err := fsys.statDir("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fsDir{fsys: fsys, name: name, info: info}, nil
+}
+
+// ReadDir implements fs.ReadDirFS, listing the directory with a delimiter so only its
+// direct children are fetched.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	dir := fsys.dirKey(name)
+	opts := append(slices.Clip(fsys.opts), WithPrefix(dir), WithDelimiter("/"))
+
+	var (
+		entries []fs.DirEntry
+		files   = map[string]bool{}
+		found   = name == "."
+	)
+
+	for pageOpts := opts; ; {
+		list, err := fsys.c.List(fsys.ctx, pageOpts...)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+		}
+
+		found = found || len(list.Items) > 0 || len(list.Prefixes) > 0
+
+		// Objects sort before the prefixes they are a part of, so files are seen
+		// before a directory of the same name, even across pages.
+		for _, obj := range list.Items {
+			base := strings.TrimPrefix(obj.Key, dir)
+			if !validName(base) {
+				continue
+			}
+			files[base] = true
+			entries = append(entries, fs.FileInfoToDirEntry(objectInfo(base, &obj)))
+		}
+
+		for _, p := range list.Prefixes {
+			base := strings.TrimSuffix(strings.TrimPrefix(p, dir), "/")
+			if !validName(base) || files[base] {
+				continue
+			}
+			entries = append(entries, fs.FileInfoToDirEntry(dirInfo(base)))
+		}
+
+		if !list.HasMore || list.NextToken == "" {
+			break
+		}
+		pageOpts = append(slices.Clip(opts), WithPaginationToken(list.NextToken))
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+// ReadFile implements fs.ReadFileFS with a single GET.
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errIsDir}
+	}
+
+	obj, err := fsys.c.Get(fsys.ctx, fsys.key(name), fsys.opts...)
+	if err != nil {
+		return nil, pathError("readfile", name, err)
+	}
+	defer obj.Body.Close()
+
+	data, err := io.ReadAll(obj.Body)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+
+	return data, nil
+}
+
+// Stat implements fs.StatFS. Files are looked up with a HEAD request, directories
+// with a listing of at most one key.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name != "." {
+		obj, err := fsys.c.Head(fsys.ctx, fsys.key(name), fsys.opts...)
+		if err == nil {
+			return objectInfo(path.Base(name), obj), nil
+		}
+		if !errors.Is(err, ErrObjectNotFound) {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+	}
+
+	return fsys.statDir("stat", name)
+}
+
+// Sub implements fs.SubFS without making any requests. Like fs.Sub, it does not check
+// that dir exists.
+func (fsys *FS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return fsys, nil
+	}
+
+	cp := *fsys
+	cp.prefix = fsys.dirKey(dir)
+	return &cp, nil
+}
+
+// key returns the object key for the file name.
+func (fsys *FS) key(name string) string {
+	if name == "." {
+		return fsys.prefix
+	}
+	return fsys.prefix + name
+}
+
+// dirKey returns the key prefix of the objects in the directory name.
+func (fsys *FS) dirKey(name string) string {
+	if name == "." {
+		return fsys.prefix
+	}
+	return fsys.prefix + name + "/"
+}
+
+// statDir returns the info of the directory name, or an fs.ErrNotExist error for op
+// if no key is under it. The root always exists.
+func (fsys *FS) statDir(op, name string) (*fileInfo, error) {
+	if name != "." {
+		list, err := fsys.c.List(fsys.ctx, append(slices.Clip(fsys.opts), WithPrefix(fsys.dirKey(name)), WithMaxKeys(1))...)
+		if err != nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+		if len(list.Items) == 0 {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+
+	return dirInfo(path.Base(name)), nil
+}
+
+// validName reports whether base, a key relative to its directory, can be a directory
+// entry.
+func validName(base string) bool {
+	return base != "." && fs.ValidPath(base)
+}
+
+// pathError wraps err for op on name, reporting a missing object as fs.ErrNotExist.
+func pathError(op, name string, err error) error {
+	if errors.Is(err, ErrObjectNotFound) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// fileInfo describes an object or a directory of an FS.
+type fileInfo struct {
+	name string
+	obj  *Object // nil for directories
+}
+
+// objectInfo returns the info of the object obj as the file name.
+func objectInfo(name string, obj *Object) *fileInfo {
+	return &fileInfo{name: name, obj: obj}
+}
+
+// dirInfo returns the info of the directory name.
+func dirInfo(name string) *fileInfo {
+	return &fileInfo{name: name}
+}
+
+// Name implements fs.FileInfo.
+func (fi *fileInfo) Name() string {
+	return fi.name
+}
+
+// Size implements fs.FileInfo.
+func (fi *fileInfo) Size() int64 {
+	if fi.obj == nil {
+		return 0
+	}
+	return fi.obj.Size
+}
+
+// Mode implements fs.FileInfo. Files are read-only.
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.obj == nil {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// ModTime implements fs.FileInfo. It is truncated to the second, because that is all
+// HEAD responses carry, so that Stat and ReadDir agree. Directories have the zero
+// time.
+func (fi *fileInfo) ModTime() time.Time {
+	if fi.obj == nil {
+		return time.Time{}
+	}
+	return fi.obj.LastModified.Truncate(time.Second)
+}
+
+// IsDir implements fs.FileInfo.
+func (fi *fileInfo) IsDir() bool {
+	return fi.obj == nil
+}
+
+// Sys implements fs.FileInfo. For files it returns the *Object with the metadata
+// from Tigris, such as the ETag; for directories it returns nil.
+func (fi *fileInfo) Sys() any {
+	if fi.obj == nil {
+		return nil
+	}
+	return fi.obj
+}
+
+// fsFile is an object opened from an FS.
+type fsFile struct {
+	*ObjectReader
+	info *fileInfo
+}
+
+// Stat implements fs.File.
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// fsDir is a directory opened from an FS. Its entries are listed on the first call to
+// ReadDir.
+type fsDir struct {
+	fsys *FS
+	name string
+	info *fileInfo
+
+	entries []fs.DirEntry
+	listed  bool
+}
+
+// Stat implements fs.File.
+func (d *fsDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Read implements fs.File. It always fails, because d is a directory.
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errIsDir}
+}
+
+// Close implements fs.File.
+func (d *fsDir) Close() error {
+	return nil
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.listed = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	entries := d.entries[:n:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+var (
+	_ fs.ReadDirFS   = (*FS)(nil)
+	_ fs.ReadFileFS  = (*FS)(nil)
+	_ fs.StatFS      = (*FS)(nil)
+	_ fs.SubFS       = (*FS)(nil)
+	_ fs.ReadDirFile = (*fsDir)(nil)
+	_ io.ReadSeeker  = (*fsFile)(nil)
+)
@@ -0,0 +1,58 @@
+package simplestorage_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_FS() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Serve the objects under site/ as static files, with Range support
+	http.Handle("/", http.FileServer(http.FS(client.FS("site/"))))
+}
+
+func ExampleClient_FS_snapshot() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read the templates as they were in a snapshot, so later writes don't mix in
+	templates := client.FS("templates/", simplestorage.WithSnapshot("1751631910169675092"))
+
+	tmpl, err := template.ParseFS(templates, "*.html")
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	err = fs.WalkDir(templates, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		fmt.Println(name)
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	_ = tmpl
+}
@@ -0,0 +1,159 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFS(t *testing.T) {
+	client := newTestClient(t)
+	for _, key := range []string{
+		"outside.txt",
+		"site/index.html",
+		"site/css/main.css",
+		"site/css/print.css",
+		"site/img/logo/big.png",
+		"site/notes",
+		"site/notes/ignored.txt",
+		"site//empty-element.txt",
+		"site/../escape.txt",
+	} {
+		putTestObject(t, client, key, []byte("contents of "+key))
+	}
+
+	fsys := client.FS("site")
+
+	if err := fstest.TestFS(fsys, "index.html", "css/main.css", "css/print.css", "img/logo/big.png", "notes"); err != nil {
+		t.Fatal(err)
+	}
+
+	var walked []string
+	if err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, p)
+		return nil
+	}); err != nil {
+		t.Fatalf("WalkDir() failed: %v", err)
+	}
+
+	want := []string{".", "css", "css/main.css", "css/print.css", "img", "img/logo", "img/logo/big.png", "index.html", "notes"}
+	if !slices.Equal(walked, want) {
+		t.Errorf("WalkDir() visited %v, want %v", walked, want)
+	}
+
+	sub, err := fs.Sub(fsys, "img")
+	if err != nil {
+		t.Fatalf("Sub() failed: %v", err)
+	}
+	if data, err := fs.ReadFile(sub, "logo/big.png"); err != nil || string(data) != "contents of site/img/logo/big.png" {
+		t.Errorf("ReadFile(logo/big.png) in Sub(img) = %q, %v", data, err)
+	}
+
+	info, err := fs.Stat(fsys, "css/main.css")
+	if err != nil {
+		t.Fatalf("Stat() failed: %v", err)
+	}
+	if obj, ok := info.Sys().(*Object); !ok || obj.Etag == "" {
+		t.Errorf("Stat().Sys() = %#v, want an *Object with an ETag", info.Sys())
+	}
+
+	for _, name := range []string{"missing.txt", "css/missing.css", "outside.txt", "index.html/x"} {
+		if _, err := fsys.Open(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Open(%q) error = %v, want fs.ErrNotExist", name, err)
+		}
+		if _, err := fs.ReadFile(fsys, name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("ReadFile(%q) error = %v, want fs.ErrNotExist", name, err)
+		}
+		if _, err := fs.Stat(fsys, name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Stat(%q) error = %v, want fs.ErrNotExist", name, err)
+		}
+	}
+
+	for _, name := range []string{"/index.html", "../outside.txt", "css/"} {
+		if _, err := fsys.Open(name); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Open(%q) error = %v, want fs.ErrInvalid", name, err)
+		}
+	}
+}
+
+func TestFS_snapshot(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "pinned", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	bucket := client.For("pinned")
+
+	putTestObject(t, bucket, "templates/page.html", []byte("old page"))
+
+	snap, err := bucket.CreateBucketSnapshot(ctx, "pinned", "release")
+	if err != nil {
+		t.Fatalf("CreateBucketSnapshot() failed: %v", err)
+	}
+
+	putTestObject(t, bucket, "templates/page.html", []byte("new page"))
+	putTestObject(t, bucket, "templates/added.html", []byte("added later"))
+
+	pinned := bucket.FS("templates", WithSnapshot(snap.Version))
+
+	if data, err := fs.ReadFile(pinned, "page.html"); err != nil || string(data) != "old page" {
+		t.Errorf("ReadFile(page.html) = %q, %v, want %q", data, err, "old page")
+	}
+
+	f, err := pinned.Open("page.html")
+	if err != nil {
+		t.Fatalf("Open(page.html) failed: %v", err)
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(data) != "old page" {
+		t.Errorf("reading opened page.html = %q, %v, want %q", data, err, "old page")
+	}
+
+	entries, err := fs.ReadDir(pinned, ".")
+	if err != nil {
+		t.Fatalf("ReadDir() failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "page.html" {
+		t.Errorf("ReadDir() = %v, want only page.html", entries)
+	}
+
+	if _, err := fs.Stat(pinned, "added.html"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(added.html) error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestFS_fileServer(t *testing.T) {
+	client := newTestClient(t)
+	putTestObject(t, client, "public/hello.txt", []byte("hello, world"))
+
+	srv := httptest.NewServer(http.FileServer(http.FS(client.FS("public"))))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/hello.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Range", "bytes=7-")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /hello.txt failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "world" {
+		t.Errorf("GET /hello.txt with Range = %d %q, want 206 %q", resp.StatusCode, body, "world")
+	}
+}
@@ -0,0 +1,431 @@
+package simplestorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	storage "github.com/tigrisdata/storage-go"
+)
+
+// IndexFormat is how Handler renders requests for "directory" prefixes.
+type IndexFormat int
+
+const (
+	IndexNone IndexFormat = iota // Directory requests get 404 Not Found (the default)
+	IndexHTML                    // Directory requests get an HTML page of links
+	IndexJSON                    // Directory requests get a JSON document
+)
+
+// HandlerOption is a functional option for Handler.
+type HandlerOption func(*HandlerOptions)
+
+// HandlerOptions is the set of options for Handler.
+type HandlerOptions struct {
+	KeyPrefix     string         // Prefix added to request paths to get object keys
+	Index         IndexFormat    // How directory requests are rendered
+	ObjectOptions []ClientOption // Options for every call, such as WithSnapshot
+}
+
+// WithKeyPrefix makes Handler serve the objects under prefix, so a request for
+// /index.html serves the object prefix+"index.html". Use http.StripPrefix to remove a
+// prefix from the request paths instead.
+func WithKeyPrefix(prefix string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.KeyPrefix = prefix
+	}
+}
+
+// WithIndex makes Handler render an index of the objects and subdirectories in
+// requests for paths that end in a slash. Requests for a directory without the
+// trailing slash are redirected to it.
+func WithIndex(format IndexFormat) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Index = format
+	}
+}
+
+// WithObjectOptions sets the options for the calls Handler makes, such as
+// OverrideBucket to serve another bucket or WithSnapshot to serve a snapshot.
+func WithObjectOptions(opts ...ClientOption) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.ObjectOptions = append(o.ObjectOptions, opts...)
+	}
+}
+
+// handler is the http.Handler returned by Handler.
+type handler struct {
+	c       *Client
+	options HandlerOptions
+	o       ClientOptions
+}
+
+// Handler returns an http.Handler that serves the objects of the client's bucket,
+// with the request path (without its leading slash) as the key. Use it to proxy a
+// private bucket:
+//
+//	http.Handle("/files/", http.StripPrefix("/files/", simplestorage.Handler(client)))
+//
+// Objects are streamed with their stored Content-Type, Content-Disposition and
+// Cache-Control. Range, If-Match, If-None-Match, If-Modified-Since and
+// If-Unmodified-Since are forwarded to Tigris as conditional and ranged reads, so
+// the handler answers with 206 Partial Content, 304 Not Modified, 412 Precondition
+// Failed or 416 Range Not Satisfiable without transferring more than needed. Requests
+// with If-Range are served in full. Missing objects get 404 Not Found.
+//
+// Only GET and HEAD requests are allowed. See WithIndex for directory listings.
+func Handler(c *Client, opts ...HandlerOption) http.Handler {
+	var ho HandlerOptions
+	for _, doer := range opts {
+		doer(&ho)
+	}
+
+	o := new(ClientOptions).defaults(c.options)
+	for _, doer := range ho.ObjectOptions {
+		doer(&o)
+	}
+
+	return &handler{
+		c:       c,
+		options: ho,
+		o:       o,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Names with empty, "." or ".." elements could reach keys outside KeyPrefix on
+	// stores that clean paths, so they are never looked up.
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	if name != "" && !fs.ValidPath(strings.TrimSuffix(name, "/")) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if name == "" || strings.HasSuffix(name, "/") {
+		h.serveIndex(w, r, name)
+		return
+	}
+
+	hdr, body, err := h.fetch(r, h.options.KeyPrefix+name)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) && h.options.Index != IndexNone && h.isDir(r.Context(), name+"/") {
+			// A relative redirect, like http.FileServer's, also works behind
+			// http.StripPrefix.
+			target := path.Base(r.URL.Path) + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			w.Header().Set("Location", target)
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+		h.serveError(w, err)
+		return
+	}
+	if body != nil {
+		defer body.Close()
+	}
+
+	for k, v := range hdr {
+		w.Header()[k] = v
+	}
+
+	status := http.StatusOK
+	if hdr.Get("Content-Range") != "" {
+		status = http.StatusPartialContent
+	}
+	w.WriteHeader(status)
+
+	if body != nil {
+		io.Copy(w, body)
+	}
+}
+
+// fetch gets the object at key for r, with r's range and conditions, and returns the
+// response headers to send. The body is nil for HEAD requests.
+func (h *handler) fetch(r *http.Request, key string) (http.Header, io.ReadCloser, error) {
+	var (
+		rng               = r.Header.Get("Range")
+		ifMatch           = r.Header.Get("If-Match")
+		ifNoneMatch       = r.Header.Get("If-None-Match")
+		ifModifiedSince   *time.Time
+		ifUnmodifiedSince *time.Time
+	)
+
+	// Without support for If-Range, the whole object has to be sent so the client
+	// never combines ranges of different versions.
+	if r.Header.Get("If-Range") != "" {
+		rng = ""
+	}
+
+	// As in RFC 9110, the date conditions only apply without the ETag conditions.
+	if t, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && ifNoneMatch == "" {
+		ifModifiedSince = &t
+	}
+	if t, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil && ifMatch == "" {
+		ifUnmodifiedSince = &t
+	}
+
+	hdr := http.Header{}
+	hdr.Set("Accept-Ranges", "bytes")
+
+	if r.Method == http.MethodHead {
+		resp, err := h.c.cli.HeadObject(r.Context(), &s3.HeadObjectInput{
+			Bucket:            aws.String(h.o.BucketName),
+			Key:               aws.String(key),
+			Range:             raise(rng),
+			IfMatch:           raise(ifMatch),
+			IfNoneMatch:       raise(ifNoneMatch),
+			IfModifiedSince:   ifModifiedSince,
+			IfUnmodifiedSince: ifUnmodifiedSince,
+		}, h.o.readOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		objectHeaders{
+			contentType:        resp.ContentType,
+			contentDisposition: resp.ContentDisposition,
+			cacheControl:       resp.CacheControl,
+			contentEncoding:    resp.ContentEncoding,
+			contentLanguage:    resp.ContentLanguage,
+			expires:            resp.ExpiresString,
+			etag:               resp.ETag,
+			lastModified:       resp.LastModified,
+			contentLength:      resp.ContentLength,
+			contentRange:       resp.ContentRange,
+		}.set(hdr)
+		return hdr, nil, nil
+	}
+
+	resp, err := h.c.cli.GetObject(r.Context(), &s3.GetObjectInput{
+		Bucket:            aws.String(h.o.BucketName),
+		Key:               aws.String(key),
+		Range:             raise(rng),
+		IfMatch:           raise(ifMatch),
+		IfNoneMatch:       raise(ifNoneMatch),
+		IfModifiedSince:   ifModifiedSince,
+		IfUnmodifiedSince: ifUnmodifiedSince,
+	}, h.o.readOptions()...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objectHeaders{
+		contentType:        resp.ContentType,
+		contentDisposition: resp.ContentDisposition,
+		cacheControl:       resp.CacheControl,
+		contentEncoding:    resp.ContentEncoding,
+		contentLanguage:    resp.ContentLanguage,
+		expires:            resp.ExpiresString,
+		etag:               resp.ETag,
+		lastModified:       resp.LastModified,
+		contentLength:      resp.ContentLength,
+		contentRange:       resp.ContentRange,
+	}.set(hdr)
+	return hdr, resp.Body, nil
+}
+
+// objectHeaders are the fields of a GetObject or HeadObject response that are
+// forwarded to the client.
+type objectHeaders struct {
+	contentType        *string
+	contentDisposition *string
+	cacheControl       *string
+	contentEncoding    *string
+	contentLanguage    *string
+	expires            *string
+	etag               *string
+	lastModified       *time.Time
+	contentLength      *int64
+	contentRange       *string
+}
+
+// set sets the response headers for the object in hdr.
+func (oh objectHeaders) set(hdr http.Header) {
+	hdr.Set("Content-Type", lower(oh.contentType, "application/octet-stream"))
+
+	for k, v := range map[string]*string{
+		"Content-Disposition": oh.contentDisposition,
+		"Cache-Control":       oh.cacheControl,
+		"Content-Encoding":    oh.contentEncoding,
+		"Content-Language":    oh.contentLanguage,
+		"Expires":             oh.expires,
+		"ETag":                oh.etag,
+		"Content-Range":       oh.contentRange,
+	} {
+		if v := lower(v, ""); v != "" {
+			hdr.Set(k, v)
+		}
+	}
+
+	if oh.lastModified != nil {
+		hdr.Set("Last-Modified", oh.lastModified.UTC().Format(http.TimeFormat))
+	}
+	if oh.contentLength != nil {
+		hdr.Set("Content-Length", strconv.FormatInt(*oh.contentLength, 10))
+	}
+}
+
+// serveError writes the response for a failed call to Tigris.
+func (h *handler) serveError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+
+	var tigrisErr *storage.Error
+	switch {
+	case errors.Is(err, ErrNotModified):
+		// Tigris sends the validators with 304 responses, and so must we.
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.Response != nil {
+			for _, k := range []string{"ETag", "Last-Modified", "Cache-Control"} {
+				if v := respErr.Response.Header.Get(k); v != "" {
+					w.Header().Set(k, v)
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return
+	case errors.Is(err, ErrObjectNotFound), errors.Is(err, ErrBucketNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, ErrAccessDenied):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrSlowDown):
+		status = http.StatusServiceUnavailable
+	case errors.As(err, &tigrisErr) && tigrisErr.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		status = http.StatusRequestedRangeNotSatisfiable
+	case errors.Is(err, context.Canceled):
+		return
+	}
+
+	http.Error(w, http.StatusText(status), status)
+}
+
+// isDir reports whether any object key starts with prefix, relative to KeyPrefix.
+func (h *handler) isDir(ctx context.Context, prefix string) bool {
+	list, err := h.c.List(ctx, append(slices.Clip(h.options.ObjectOptions), WithPrefix(h.options.KeyPrefix+prefix), WithMaxKeys(1))...)
+	return err == nil && len(list.Items) > 0
+}
+
+// indexEntry is an object or subdirectory in a directory index.
+type indexEntry struct {
+	Name         string    `json:"name"`
+	Dir          bool      `json:"dir,omitempty"`
+	Size         int64     `json:"size,omitempty"`
+	LastModified time.Time `json:"lastModified,omitzero"`
+	Etag         string    `json:"etag,omitempty"`
+}
+
+// index is the JSON document of a directory index.
+type index struct {
+	Path    string       `json:"path"`
+	Entries []indexEntry `json:"entries"`
+}
+
+// serveIndex renders the directory name, which is empty or ends in a slash.
+func (h *handler) serveIndex(w http.ResponseWriter, r *http.Request, name string) {
+	if h.options.Index == IndexNone {
+		http.NotFound(w, r)
+		return
+	}
+
+	dir := h.options.KeyPrefix + name
+	opts := append(slices.Clip(h.options.ObjectOptions), WithPrefix(dir), WithDelimiter("/"))
+
+	idx := index{Path: "/" + name, Entries: []indexEntry{}}
+	for pageOpts := opts; ; {
+		list, err := h.c.List(r.Context(), pageOpts...)
+		if err != nil {
+			h.serveError(w, err)
+			return
+		}
+
+		for _, obj := range list.Items {
+			if base := strings.TrimPrefix(obj.Key, dir); validName(base) {
+				idx.Entries = append(idx.Entries, indexEntry{
+					Name:         base,
+					Size:         obj.Size,
+					LastModified: obj.LastModified,
+					Etag:         obj.Etag,
+				})
+			}
+		}
+		for _, p := range list.Prefixes {
+			if base := strings.TrimPrefix(p, dir); validName(strings.TrimSuffix(base, "/")) {
+				idx.Entries = append(idx.Entries, indexEntry{Name: base, Dir: true})
+			}
+		}
+
+		if !list.HasMore || list.NextToken == "" {
+			break
+		}
+		pageOpts = append(slices.Clip(opts), WithPaginationToken(list.NextToken))
+	}
+
+	if len(idx.Entries) == 0 && name != "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	slices.SortFunc(idx.Entries, func(a, b indexEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if h.options.Index == IndexJSON {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodHead {
+			json.NewEncoder(w).Encode(idx)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.Method != http.MethodHead {
+		indexTemplate.Execute(w, idx)
+	}
+}
+
+// indexTemplate renders an HTML directory index.
+var indexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{
+	"href": func(name string) string {
+		return (&url.URL{Path: name}).String()
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+{{- if ne .Path "/"}}
+<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{- end}}
+{{- range .Entries}}
+<tr><td><a href="{{href .Name}}">{{.Name}}</a></td><td>{{if not .Dir}}{{.Size}}{{end}}</td><td>{{if not .Dir}}{{.LastModified.UTC.Format "2006-01-02 15:04:05"}}{{end}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
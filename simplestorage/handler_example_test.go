@@ -0,0 +1,29 @@
+package simplestorage_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleHandler() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-private-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Serve downloads/* from the objects under public/, with an HTML index of each
+	// directory
+	http.Handle("/downloads/", http.StripPrefix("/downloads/", simplestorage.Handler(client,
+		simplestorage.WithKeyPrefix("public/"),
+		simplestorage.WithIndex(simplestorage.IndexHTML),
+	)))
+
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,385 @@
+package simplestorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// putServedObject puts an object with the headers Handler passes through.
+func putServedObject(t *testing.T, client *Client, key, data string) string {
+	t.Helper()
+
+	resp, err := client.cli.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:             aws.String(client.options.BucketName),
+		Key:                aws.String(key),
+		Body:               bytes.NewReader([]byte(data)),
+		ContentType:        aws.String("text/plain"),
+		ContentDisposition: aws.String(`attachment; filename="report.txt"`),
+		CacheControl:       aws.String("max-age=60"),
+	})
+	if err != nil {
+		t.Fatalf("PutObject(%q) failed: %v", key, err)
+	}
+
+	return aws.ToString(resp.ETag)
+}
+
+// serve sends a request for target with the given headers to h.
+func serve(h http.Handler, method, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	client := newTestClient(t)
+	etag := putServedObject(t, client, "docs/report.txt", "0123456789")
+	putTestObject(t, client, "docs/other.bin", []byte("x"))
+
+	h := Handler(client)
+
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "get",
+			target:     "/docs/report.txt",
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+			wantHeader: map[string]string{
+				"Content-Type":        "text/plain",
+				"Content-Disposition": `attachment; filename="report.txt"`,
+				"Cache-Control":       "max-age=60",
+				"Content-Length":      "10",
+				"Accept-Ranges":       "bytes",
+				"ETag":                etag,
+			},
+		},
+		{
+			name:       "default content type",
+			target:     "/docs/other.bin",
+			wantStatus: http.StatusOK,
+			wantBody:   "x",
+		},
+		{
+			name:       "head",
+			method:     http.MethodHead,
+			target:     "/docs/report.txt",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Content-Length": "10", "ETag": etag},
+		},
+		{
+			name:       "range",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"Range": "bytes=2-5"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "2345",
+			wantHeader: map[string]string{"Content-Range": "bytes 2-5/10", "Content-Length": "4"},
+		},
+		{
+			name:       "suffix range",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"Range": "bytes=-3"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "789",
+		},
+		{
+			name:       "unsatisfiable range",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"Range": "bytes=20-"},
+			wantStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
+		{
+			name:       "range with if-range",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"Range": "bytes=2-5", "If-Range": `"stale"`},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+		},
+		{
+			name:       "if-none-match hit",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-None-Match": etag},
+			wantStatus: http.StatusNotModified,
+			wantHeader: map[string]string{"ETag": etag},
+		},
+		{
+			name:       "if-none-match miss",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-None-Match": `"stale"`},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+		},
+		{
+			name:       "if-none-match wins over if-modified-since",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": future},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+		},
+		{
+			name:       "if-modified-since unchanged",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-Modified-Since": future},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "if-modified-since changed",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-Modified-Since": past},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+		},
+		{
+			name:       "head not modified",
+			method:     http.MethodHead,
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-None-Match": etag},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "if-match failed",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-Match": `"stale"`},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "if-unmodified-since failed",
+			target:     "/docs/report.txt",
+			headers:    map[string]string{"If-Unmodified-Since": past},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "missing object",
+			target:     "/docs/missing.txt",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "directory without index",
+			target:     "/docs/",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "directory without index or slash",
+			target:     "/docs",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "dot dot",
+			target:     "/docs/../docs/report.txt",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "post",
+			method:     http.MethodPost,
+			target:     "/docs/report.txt",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantHeader: map[string]string{"Allow": "GET, HEAD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			rec := serve(h, method, tt.target, tt.headers)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" || method == http.MethodHead || tt.wantStatus == http.StatusNotModified {
+				if got := rec.Body.String(); got != tt.wantBody {
+					t.Errorf("body = %q, want %q", got, tt.wantBody)
+				}
+			}
+			for k, want := range tt.wantHeader {
+				if got := rec.Header().Get(k); got != want {
+					t.Errorf("header %s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_index(t *testing.T) {
+	client := newTestClient(t)
+	for _, key := range []string{"site/index.html", "site/a b.txt", "site/css/main.css", "site/css/print.css", "site/js/app.js"} {
+		putTestObject(t, client, key, []byte(key))
+	}
+
+	t.Run("json", func(t *testing.T) {
+		h := Handler(client, WithIndex(IndexJSON))
+
+		rec := serve(h, http.MethodGet, "/site/", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+
+		var idx index
+		if err := json.Unmarshal(rec.Body.Bytes(), &idx); err != nil {
+			t.Fatalf("can't decode index: %v", err)
+		}
+
+		var names []string
+		for _, e := range idx.Entries {
+			names = append(names, e.Name)
+			if e.Name == "index.html" && (e.Size != int64(len("site/index.html")) || e.Etag == "" || e.LastModified.IsZero()) {
+				t.Errorf("index.html entry = %+v, want its size, ETag and modification time", e)
+			}
+		}
+
+		if want := "a b.txt css/ index.html js/"; strings.Join(names, " ") != want {
+			t.Errorf("entries = %q, want %q", strings.Join(names, " "), want)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		h := Handler(client, WithIndex(IndexHTML))
+
+		rec := serve(h, http.MethodGet, "/site/", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+
+		body := rec.Body.String()
+		for _, want := range []string{`href="../"`, `href="a%20b.txt"`, `href="css/"`, `>index.html<`} {
+			if !strings.Contains(body, want) {
+				t.Errorf("index is missing %s:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		h := Handler(client, WithIndex(IndexHTML))
+
+		rec := serve(h, http.MethodGet, "/site/css?x=1", nil)
+		if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "css/?x=1" {
+			t.Errorf("GET /site/css = %d to %q, want 301 to %q", rec.Code, rec.Header().Get("Location"), "css/?x=1")
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		h := Handler(client, WithIndex(IndexJSON))
+
+		if rec := serve(h, http.MethodGet, "/nothing/", nil); rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", rec.Code)
+		}
+		if rec := serve(h, http.MethodGet, "/site/nothing", nil); rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("key prefix", func(t *testing.T) {
+		h := http.StripPrefix("/static/", Handler(client, WithKeyPrefix("site/"), WithIndex(IndexJSON)))
+
+		rec := serve(h, http.MethodGet, "/static/css/main.css", nil)
+		if rec.Code != http.StatusOK || rec.Body.String() != "site/css/main.css" {
+			t.Errorf("GET /static/css/main.css = %d %q", rec.Code, rec.Body.String())
+		}
+
+		rec = serve(h, http.MethodGet, "/static/", nil)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"index.html"`) {
+			t.Errorf("GET /static/ = %d %q, want the index of site/", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestHandler_snapshot(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	if _, err := client.CreateBucket(ctx, "pinned", WithEnableSnapshot()); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	bucket := client.For("pinned")
+
+	putTestObject(t, bucket, "page.html", []byte("old"))
+	snap, err := bucket.CreateBucketSnapshot(ctx, "pinned", "release")
+	if err != nil {
+		t.Fatalf("CreateBucketSnapshot() failed: %v", err)
+	}
+	putTestObject(t, bucket, "page.html", []byte("new"))
+
+	h := Handler(client, WithObjectOptions(OverrideBucket("pinned"), WithSnapshot(snap.Version)))
+
+	if rec := serve(h, http.MethodGet, "/page.html", nil); rec.Body.String() != "old" {
+		t.Errorf("GET /page.html = %q, want %q", rec.Body.String(), "old")
+	}
+}
+
+func TestHandler_gzip(t *testing.T) {
+	client := newTestClient(t)
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("console.log('hello')"))
+	zw.Close()
+
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := client.cli.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:          aws.String(client.options.BucketName),
+		Key:             aws.String("app.js"),
+		Body:            bytes.NewReader(compressed.Bytes()),
+		ContentType:     aws.String("text/javascript"),
+		ContentEncoding: aws.String("gzip"),
+		ContentLanguage: aws.String("en"),
+		Expires:         &expires,
+	}); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+
+	h := Handler(client)
+	want := map[string]string{
+		"Content-Encoding": "gzip",
+		"Content-Language": "en",
+		"Expires":          expires.Format(http.TimeFormat),
+		"Content-Length":   strconv.Itoa(compressed.Len()),
+	}
+
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		rec := serve(h, method, "/app.js", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s /app.js status = %d, want 200", method, rec.Code)
+		}
+		for k, v := range want {
+			if got := rec.Header().Get(k); got != v {
+				t.Errorf("%s /app.js %s = %q, want %q", method, k, got, v)
+			}
+		}
+
+		if method == http.MethodGet && !bytes.Equal(rec.Body.Bytes(), compressed.Bytes()) {
+			t.Errorf("GET /app.js body = %q, want the stored gzip stream", rec.Body.Bytes())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package simplestorage_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_ListAll() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Pages of 500 keys are fetched as the loop needs them
+	var total int64
+	for obj, err := range client.ListAll(ctx,
+		simplestorage.WithPrefix("logs/2026/"),
+		simplestorage.WithMaxKeys(500),
+	) {
+		if err != nil {
+			log.Fatal(err) // handle the error here
+		}
+
+		total += obj.Size
+	}
+
+	fmt.Println("Total size of logs:", total)
+}
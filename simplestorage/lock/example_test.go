@@ -0,0 +1,60 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/simplestorage/lock"
+)
+
+func ExampleLocker_Acquire() {
+	ctx := context.Background()
+
+	client, err := storage.New(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	locker := lock.New(client, "my-locks-bucket")
+
+	lk, err := locker.Acquire(ctx, "jobs/nightly-report", time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer lk.Release(ctx)
+
+	// Pass the fencing token along with writes so stale holders can be rejected
+	fmt.Println("Running as holder", lk.Token())
+
+	// Renew well before the lease runs out while the work is in progress
+	if err := lk.Renew(ctx); errors.Is(err, lock.ErrLockLost) {
+		log.Fatal("lease was taken over, stopping")
+	}
+}
+
+func ExampleLocker_TryAcquire() {
+	ctx := context.Background()
+
+	client, err := storage.New(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	locker := lock.New(client, "my-locks-bucket")
+
+	lk, err := locker.TryAcquire(ctx, "cron/leader", 30*time.Second)
+	switch {
+	case errors.Is(err, lock.ErrLocked):
+		fmt.Println("Another process is the leader")
+		return
+	case err != nil:
+		log.Fatal(err)
+	}
+	defer lk.Release(ctx)
+
+	fmt.Println("Elected leader until", lk.Expires())
+}
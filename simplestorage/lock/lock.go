@@ -0,0 +1,341 @@
+// Package lock implements leases on top of Tigris conditional writes, for mutual
+// exclusion between processes that share a bucket, such as electing a cron leader or
+// making sure only one worker runs a migration.
+//
+// A lock is an object in the bucket that records its holder, when the lease expires
+// and a fencing token. Locks are taken with create-if-absent and ETag-matching writes
+// after a consistent (compare-and-swap) read, so at most one holder can succeed. A
+// holder that stops renewing loses the lock once its lease expires, and the next
+// Acquire takes it over.
+//
+// Each successful acquisition gets a fencing token that is larger than every token
+// handed out before for the same key. Pass it along with writes to other systems so
+// they can reject a holder whose lease has already been taken over.
+//
+// Expiry is judged by the clocks of the competing processes, so they should be
+// roughly synchronized, and the TTL should be much longer than the expected clock
+// skew.
+package lock
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+var (
+	// ErrLocked is returned by TryAcquire when another holder has an unexpired lease.
+	ErrLocked = errors.New("lock: held by another owner")
+
+	// ErrLockLost is returned by Renew and Release when the lease expired and was taken
+	// over, or the lock was otherwise changed behind the holder's back.
+	ErrLockLost = errors.New("lock: lease lost")
+)
+
+// DefaultRetryInterval is how long Acquire waits between attempts unless overridden
+// with WithRetryInterval.
+const DefaultRetryInterval = time.Second
+
+// Options is the set of options for a Locker.
+type Options struct {
+	Owner         string        // Identifies this process in the lock object, for debugging
+	RetryInterval time.Duration // How long Acquire waits between attempts
+}
+
+// defaults returns the default options for a Locker.
+func (Options) defaults() Options {
+	return Options{
+		Owner:         defaultOwner(),
+		RetryInterval: DefaultRetryInterval,
+	}
+}
+
+// Option is a functional option for configuring a Locker.
+type Option func(*Options)
+
+// WithOwner sets the owner name recorded in locks taken by the Locker. It defaults to
+// the hostname followed by a random suffix, which an empty owner keeps: a lock without
+// an owner reads as released.
+func WithOwner(owner string) Option {
+	return func(o *Options) {
+		if owner != "" {
+			o.Owner = owner
+		}
+	}
+}
+
+// WithRetryInterval sets how long Acquire waits between attempts to take a held lock.
+func WithRetryInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.RetryInterval = d
+	}
+}
+
+// Locker takes locks stored as objects in a bucket.
+type Locker struct {
+	cli     *storage.Client
+	bucket  string
+	options Options
+	now     func() time.Time
+}
+
+// New returns a Locker that stores locks in bucket.
+func New(cli *storage.Client, bucket string, opts ...Option) *Locker {
+	o := new(Options).defaults()
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	return &Locker{
+		cli:     cli,
+		bucket:  bucket,
+		options: o,
+		now:     time.Now,
+	}
+}
+
+// state is the JSON document stored in a lock object.
+type state struct {
+	Owner   string    `json:"owner,omitempty"`
+	Token   uint64    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// held reports whether the lease in s is still valid at now.
+func (s state) held(now time.Time) bool {
+	return s.Owner != "" && now.Before(s.Expires)
+}
+
+// Lock is a held lease on a key.
+type Lock struct {
+	l     *Locker
+	key   string
+	token uint64
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	etag     string
+	expires  time.Time
+	released bool
+}
+
+// Key returns the object key of the lock.
+func (lk *Lock) Key() string {
+	return lk.key
+}
+
+// Token returns the fencing token of this acquisition. It is larger than the token of
+// any earlier acquisition of the same key.
+func (lk *Lock) Token() uint64 {
+	return lk.token
+}
+
+// Expires returns when the lease runs out unless it is renewed.
+func (lk *Lock) Expires() time.Time {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	return lk.expires
+}
+
+// Acquire takes the lock at key for ttl, waiting for the current holder to release it
+// or for its lease to expire. It returns ctx's error if ctx is done first.
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	for {
+		lk, err := l.TryAcquire(ctx, key, ttl)
+		if !errors.Is(err, ErrLocked) {
+			return lk, err
+		}
+
+		t := time.NewTimer(l.options.RetryInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, fmt.Errorf("lock: can't acquire %s/%s: %w", l.bucket, key, context.Cause(ctx))
+		case <-t.C:
+		}
+	}
+}
+
+// TryAcquire takes the lock at key for ttl if it is free or its lease has expired.
+// Otherwise it returns an error wrapping ErrLocked.
+func (l *Locker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("lock: can't acquire %s/%s: ttl must be positive, got %v", l.bucket, key, ttl)
+	}
+
+	cur, etag, err := l.read(ctx, key)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("lock: can't acquire %s/%s: %w", l.bucket, key, err)
+	}
+
+	now := l.now()
+	if err == nil && cur.held(now) {
+		return nil, fmt.Errorf("lock: can't acquire %s/%s: owner %s holds it until %s: %w", l.bucket, key, cur.Owner, cur.Expires.Format(time.RFC3339), ErrLocked)
+	}
+
+	next := state{
+		Owner:   l.options.Owner,
+		Token:   cur.Token + 1,
+		Expires: now.Add(ttl),
+	}
+
+	// A missing lock object is created only if nobody else creates it first;
+	// otherwise the write only succeeds if the object is unchanged since the read.
+	cond := tigrisheaders.WithCreateObjectIfNotExists()
+	if etag != "" {
+		cond = tigrisheaders.WithIfEtagMatches(etag)
+	}
+
+	newEtag, err := l.write(ctx, key, next, cond)
+	if err != nil {
+		if errors.Is(err, storage.ErrPreconditionFailed) || errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, fmt.Errorf("lock: can't acquire %s/%s: another owner took it first: %w", l.bucket, key, ErrLocked)
+		}
+		return nil, fmt.Errorf("lock: can't acquire %s/%s: %w", l.bucket, key, err)
+	}
+
+	return &Lock{
+		l:       l,
+		key:     key,
+		token:   next.Token,
+		ttl:     ttl,
+		etag:    newEtag,
+		expires: next.Expires,
+	}, nil
+}
+
+// Renew extends the lease by the TTL it was acquired with, counted from now. It
+// returns an error wrapping ErrLockLost if the lease was taken over.
+func (lk *Lock) Renew(ctx context.Context) error {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.released {
+		return fmt.Errorf("lock: can't renew %s/%s: already released: %w", lk.l.bucket, lk.key, ErrLockLost)
+	}
+
+	next := state{
+		Owner:   lk.l.options.Owner,
+		Token:   lk.token,
+		Expires: lk.l.now().Add(lk.ttl),
+	}
+
+	etag, err := lk.l.write(ctx, lk.key, next, tigrisheaders.WithIfEtagMatches(lk.etag))
+	if err != nil {
+		return lk.l.lostOr("renew", lk.key, err)
+	}
+
+	lk.etag = etag
+	lk.expires = next.Expires
+
+	return nil
+}
+
+// Release gives up the lease so others can acquire the lock right away. It returns
+// an error wrapping ErrLockLost if the lease was already taken over.
+//
+// The lock object is kept, marked as free, so fencing tokens keep increasing.
+func (lk *Lock) Release(ctx context.Context) error {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.released {
+		return fmt.Errorf("lock: can't release %s/%s: already released: %w", lk.l.bucket, lk.key, ErrLockLost)
+	}
+
+	next := state{Token: lk.token}
+
+	etag, err := lk.l.write(ctx, lk.key, next, tigrisheaders.WithIfEtagMatches(lk.etag))
+	if err != nil {
+		return lk.l.lostOr("release", lk.key, err)
+	}
+
+	lk.etag = etag
+	lk.expires = time.Time{}
+	lk.released = true
+
+	return nil
+}
+
+// lostOr wraps err for a failed renew or release, reporting a failed precondition as
+// ErrLockLost.
+func (l *Locker) lostOr(verb, key string, err error) error {
+	if errors.Is(err, storage.ErrPreconditionFailed) || errors.Is(err, storage.ErrObjectNotFound) {
+		return fmt.Errorf("lock: can't %s %s/%s: %w", verb, l.bucket, key, ErrLockLost)
+	}
+	return fmt.Errorf("lock: can't %s %s/%s: %w", verb, l.bucket, key, err)
+}
+
+// read fetches the lock object at key with a consistent read, returning its state and
+// ETag.
+func (l *Locker) read(ctx context.Context, key string) (state, string, error) {
+	resp, err := l.cli.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(l.bucket),
+		Key:    aws.String(key),
+	}, tigrisheaders.WithCompareAndSwap())
+	if err != nil {
+		return state{}, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return state{}, "", err
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state{}, "", fmt.Errorf("object is not a lock: %w", err)
+	}
+
+	return st, aws.ToString(resp.ETag), nil
+}
+
+// write stores st at key under the given precondition and returns the new ETag.
+func (l *Locker) write(ctx context.Context, key string, st state, cond func(*s3.Options)) (string, error) {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := l.cli.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(l.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}, cond)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(resp.ETag), nil
+}
+
+// defaultOwner returns the hostname followed by a random suffix, so two processes on
+// the same host get different owner names.
+func defaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	var suffix [4]byte
+	rand.Read(suffix[:])
+
+	return host + "-" + hex.EncodeToString(suffix[:])
+}
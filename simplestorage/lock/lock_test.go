@@ -0,0 +1,211 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/tigristest"
+)
+
+// newTestClient returns a storage client backed by an in-process fake Tigris server,
+// with a bucket named "locks".
+func newTestClient(t *testing.T) *storage.Client {
+	t.Helper()
+
+	srv := tigristest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := storage.New(context.Background(),
+		storage.WithEndpoint(srv.URL),
+		storage.WithPathStyle(true),
+		storage.WithAccessKeypair("test", "test"),
+	)
+	if err != nil {
+		t.Fatalf("storage.New() failed: %v", err)
+	}
+
+	if _, err := client.CreateBucket(context.Background(), &s3.CreateBucketInput{Bucket: aws.String("locks")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	return client
+}
+
+// fakeClock is a manually advanced clock shared by Lockers in a test.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLockers(t *testing.T) (*Locker, *Locker, *fakeClock) {
+	t.Helper()
+
+	client := newTestClient(t)
+	clock := &fakeClock{t: time.Now()}
+
+	a := New(client, "locks", WithOwner("a"), WithRetryInterval(10*time.Millisecond))
+	b := New(client, "locks", WithOwner("b"), WithRetryInterval(10*time.Millisecond))
+	a.now = clock.now
+	b.now = clock.now
+
+	return a, b, clock
+}
+
+func TestLocker_mutualExclusion(t *testing.T) {
+	ctx := context.Background()
+	a, b, _ := newTestLockers(t)
+
+	la, err := a.TryAcquire(ctx, "leader", time.Minute)
+	if err != nil {
+		t.Fatalf("TryAcquire() failed: %v", err)
+	}
+	if la.Token() != 1 || la.Key() != "leader" {
+		t.Errorf("first lock = key %q token %d, want leader token 1", la.Key(), la.Token())
+	}
+
+	if _, err := b.TryAcquire(ctx, "leader", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryAcquire() of a held lock error = %v, want ErrLocked", err)
+	}
+	if _, err := a.TryAcquire(ctx, "leader", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryAcquire() by the same Locker error = %v, want ErrLocked", err)
+	}
+
+	if err := la.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if err := la.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("second Release() error = %v, want ErrLockLost", err)
+	}
+	if err := la.Renew(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Renew() after Release() error = %v, want ErrLockLost", err)
+	}
+
+	lb, err := b.TryAcquire(ctx, "leader", time.Minute)
+	if err != nil {
+		t.Fatalf("TryAcquire() after Release() failed: %v", err)
+	}
+	if lb.Token() <= la.Token() {
+		t.Errorf("token after release = %d, want more than %d", lb.Token(), la.Token())
+	}
+}
+
+func TestLocker_expiryTakeover(t *testing.T) {
+	ctx := context.Background()
+	a, b, clock := newTestLockers(t)
+
+	la, err := a.TryAcquire(ctx, "migration", time.Minute)
+	if err != nil {
+		t.Fatalf("TryAcquire() failed: %v", err)
+	}
+
+	clock.advance(30 * time.Second)
+	if err := la.Renew(ctx); err != nil {
+		t.Fatalf("Renew() failed: %v", err)
+	}
+	if want := clock.now().Add(time.Minute); !la.Expires().Equal(want) {
+		t.Errorf("Expires() after Renew() = %v, want %v", la.Expires(), want)
+	}
+
+	clock.advance(45 * time.Second)
+	if _, err := b.TryAcquire(ctx, "migration", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryAcquire() of a renewed lock error = %v, want ErrLocked", err)
+	}
+
+	clock.advance(time.Minute)
+	lb, err := b.TryAcquire(ctx, "migration", time.Minute)
+	if err != nil {
+		t.Fatalf("TryAcquire() of an expired lock failed: %v", err)
+	}
+	if lb.Token() <= la.Token() {
+		t.Errorf("token after takeover = %d, want more than %d", lb.Token(), la.Token())
+	}
+
+	if err := la.Renew(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Renew() by the old holder error = %v, want ErrLockLost", err)
+	}
+	if err := la.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Release() by the old holder error = %v, want ErrLockLost", err)
+	}
+	if err := lb.Renew(ctx); err != nil {
+		t.Errorf("Renew() by the new holder failed: %v", err)
+	}
+}
+
+func TestLocker_Acquire(t *testing.T) {
+	ctx := context.Background()
+	a, b, _ := newTestLockers(t)
+
+	la, err := a.Acquire(ctx, "job", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+
+	t.Run("waits for release", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			la.Release(ctx)
+		}()
+
+		lb, err := b.Acquire(ctx, "job", time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire() failed: %v", err)
+		}
+		if lb.Token() != la.Token()+1 {
+			t.Errorf("Token() = %d, want %d", lb.Token(), la.Token()+1)
+		}
+	})
+
+	t.Run("gives up when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		if _, err := a.Acquire(ctx, "job", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Acquire() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func TestLocker_emptyOwner(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	a := New(client, "locks", WithOwner(""))
+	b := New(client, "locks", WithOwner(""))
+	if a.options.Owner == "" {
+		t.Fatal("WithOwner(\"\") left the owner empty, want the default")
+	}
+
+	if _, err := a.TryAcquire(ctx, "leader", time.Minute); err != nil {
+		t.Fatalf("TryAcquire() failed: %v", err)
+	}
+	if _, err := b.TryAcquire(ctx, "leader", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("TryAcquire() of a held lock error = %v, want ErrLocked", err)
+	}
+}
+
+func TestLocker_TryAcquire_invalid(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	l := New(client, "locks")
+
+	if _, err := l.TryAcquire(ctx, "lock", 0); err == nil {
+		t.Error("TryAcquire() with zero ttl succeeded, want error")
+	}
+
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("locks"),
+		Key:    aws.String("not-a-lock"),
+		Body:   strings.NewReader("plain text"),
+	}); err != nil {
+		t.Fatalf("PutObject() failed: %v", err)
+	}
+	if _, err := l.TryAcquire(ctx, "not-a-lock", time.Minute); err == nil || errors.Is(err, ErrLocked) {
+		t.Errorf("TryAcquire() of a non-lock object error = %v, want a decode error", err)
+	}
+}
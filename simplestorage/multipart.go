@@ -0,0 +1,198 @@
+package simplestorage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+// PresignedMultipartUpload is a multipart upload started by PresignMultipartUpload,
+// with a presigned URL for uploading each part.
+//
+// Send each part with a PUT request to its URL. Every part except the last must be at
+// least MinPartSize bytes. Keep the ETag response header of each part and pass them to
+// CompleteMultipartUpload; browsers can only read it if the bucket's CORS rules expose
+// the ETag header.
+type PresignedMultipartUpload struct {
+	// Key is the key of the object being uploaded.
+	Key string
+
+	// UploadID identifies the upload in CompleteMultipartUpload and AbortMultipartUpload.
+	UploadID string
+
+	// PartURLs are the presigned URLs of the parts; PartURLs[i] uploads part i+1.
+	PartURLs []string
+
+	// Expires is the time after which the part URLs are no longer valid.
+	Expires time.Time
+}
+
+// CompletedPart is a part of a multipart upload that has been uploaded.
+type CompletedPart struct {
+	PartNumber int32  // Number of the part, starting at 1
+	ETag       string // ETag returned when the part was uploaded
+}
+
+// PresignMultipartUpload starts a multipart upload of key and presigns a URL for each
+// of its partCount parts, valid for expiry. This lets browsers upload objects larger
+// than the 5 GB a single presigned PUT allows, and retry individual parts.
+//
+// The object only appears once CompleteMultipartUpload is called with the ETags of
+// the uploaded parts. Call AbortMultipartUpload if the upload is abandoned so the
+// parts don't linger and get billed.
+//
+// WithContentType, WithContentDisposition, WithCacheControl and WithMetadata set the
+// headers of the uploaded object.
+func (c *Client) PresignMultipartUpload(ctx context.Context, key string, partCount int, expiry time.Duration, opts ...ClientOption) (*PresignedMultipartUpload, error) {
+	if key == "" {
+		return nil, fmt.Errorf("simplestorage: key cannot be empty for presigned multipart upload")
+	}
+
+	if partCount < 1 || partCount > MaxParts {
+		return nil, fmt.Errorf("simplestorage: invalid part count %d for presigned multipart upload (must be between 1 and %d)", partCount, MaxParts)
+	}
+
+	if expiry <= 0 {
+		return nil, fmt.Errorf("simplestorage: invalid expiry duration %v for presigned multipart upload (must be positive)", expiry)
+	}
+
+	o := new(ClientOptions).defaults(c.options)
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	create, err := c.cli.CreateMultipartUpload(
+		ctx,
+		&s3.CreateMultipartUploadInput{
+			Bucket:             aws.String(o.BucketName),
+			Key:                aws.String(key),
+			ContentType:        o.ContentType,
+			ContentDisposition: o.ContentDisposition,
+			CacheControl:       o.CacheControl,
+			Metadata:           o.Metadata,
+		},
+		o.S3Options...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't presign multipart upload of %s/%s: %w", o.BucketName, key, err)
+	}
+
+	upload := &PresignedMultipartUpload{
+		Key:      key,
+		UploadID: lower(create.UploadId, ""),
+		PartURLs: make([]string, partCount),
+		Expires:  time.Now().Add(expiry),
+	}
+
+	presignClient := s3.NewPresignClient(c.cli.Client)
+
+	for i := range upload.PartURLs {
+		req, err := presignClient.PresignUploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(o.BucketName),
+			Key:        aws.String(key),
+			UploadId:   create.UploadId,
+			PartNumber: aws.Int32(int32(i + 1)),
+		}, s3.WithPresignExpires(expiry), s3.WithPresignClientFromClientOptions(o.S3Options...))
+		if err != nil {
+			err = fmt.Errorf("presigning part %d: %w", i+1, err)
+
+			// Abort even if ctx was cancelled so the upload doesn't linger.
+			if _, abortErr := c.cli.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(o.BucketName),
+				Key:      aws.String(key),
+				UploadId: create.UploadId,
+			}, o.S3Options...); abortErr != nil {
+				err = errors.Join(err, fmt.Errorf("aborting multipart upload: %w", abortErr))
+			}
+			return nil, fmt.Errorf("simplestorage: can't presign multipart upload of %s/%s: %w", o.BucketName, key, err)
+		}
+
+		upload.PartURLs[i] = req.URL
+	}
+
+	return upload, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts of a multipart upload into the
+// object key. Parts may be given in any order, but every part uploaded must be listed
+// with the ETag it was uploaded with.
+//
+// With WithNoOverwrite, the upload fails with ErrPreconditionFailed if the object
+// already exists.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart, opts ...ClientOption) (*Object, error) {
+	o := new(ClientOptions).defaults(c.options)
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("simplestorage: can't complete multipart upload of %s/%s: no parts", o.BucketName, key)
+	}
+
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, p := range slices.SortedFunc(slices.Values(parts), func(a, b CompletedPart) int {
+		return cmp.Compare(a.PartNumber, b.PartNumber)
+	}) {
+		completed = append(completed, types.CompletedPart{
+			PartNumber: aws.Int32(p.PartNumber),
+			ETag:       aws.String(p.ETag),
+		})
+	}
+
+	s3Opts := o.S3Options
+	if o.NoOverwrite {
+		s3Opts = append(slices.Clip(s3Opts), tigrisheaders.WithCreateObjectIfNotExists())
+	}
+
+	resp, err := c.cli.CompleteMultipartUpload(
+		ctx,
+		&s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(o.BucketName),
+			Key:             aws.String(key),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		},
+		s3Opts...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't complete multipart upload of %s/%s: %w", o.BucketName, key, err)
+	}
+
+	return &Object{
+		Bucket:  o.BucketName,
+		Key:     key,
+		Etag:    lower(resp.ETag, ""),
+		Version: lower(resp.VersionId, ""),
+	}, nil
+}
+
+// AbortMultipartUpload abandons a multipart upload and deletes the parts uploaded so
+// far.
+func (c *Client) AbortMultipartUpload(ctx context.Context, key, uploadID string, opts ...ClientOption) error {
+	o := new(ClientOptions).defaults(c.options)
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	if _, err := c.cli.AbortMultipartUpload(
+		ctx,
+		&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(o.BucketName),
+			Key:      aws.String(key),
+			UploadId: aws.String(uploadID),
+		},
+		o.S3Options...,
+	); err != nil {
+		return fmt.Errorf("simplestorage: can't abort multipart upload of %s/%s: %w", o.BucketName, key, err)
+	}
+
+	return nil
+}
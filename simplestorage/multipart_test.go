@@ -0,0 +1,153 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// putPart uploads data to a presigned part URL and returns the part's ETag.
+func putPart(t *testing.T, partURL, data string) string {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPut, partURL, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PUT part failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT part status = %d, want 200", resp.StatusCode)
+	}
+	return resp.Header.Get("ETag")
+}
+
+func TestPresignMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	upload, err := client.PresignMultipartUpload(ctx, "videos/talk.mp4", 3, time.Hour,
+		WithContentType("video/mp4"),
+		WithMetadata(map[string]string{"speaker": "alice"}),
+	)
+	if err != nil {
+		t.Fatalf("PresignMultipartUpload() failed: %v", err)
+	}
+
+	if upload.Key != "videos/talk.mp4" || upload.UploadID == "" || len(upload.PartURLs) != 3 {
+		t.Fatalf("PresignMultipartUpload() = %+v, want 3 part URLs for the upload", upload)
+	}
+	if !upload.Expires.After(time.Now()) {
+		t.Errorf("Expires = %v, want a time in the future", upload.Expires)
+	}
+
+	chunks := []string{"first part, ", "second part, ", "third part"}
+
+	// Upload the parts out of order, like concurrent browser requests finishing.
+	var parts []CompletedPart
+	for _, i := range []int{2, 0, 1} {
+		etag := putPart(t, upload.PartURLs[i], chunks[i])
+		parts = append(parts, CompletedPart{PartNumber: int32(i + 1), ETag: etag})
+	}
+
+	obj, err := client.CompleteMultipartUpload(ctx, upload.Key, upload.UploadID, parts)
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload() failed: %v", err)
+	}
+	if !strings.HasSuffix(obj.Etag, `-3"`) {
+		t.Errorf("Etag = %s, want a multipart ETag of 3 parts", obj.Etag)
+	}
+
+	got, err := client.Get(ctx, "videos/talk.mp4")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	defer got.Body.Close()
+
+	data, _ := io.ReadAll(got.Body)
+	if want := strings.Join(chunks, ""); string(data) != want {
+		t.Errorf("Get() = %q, want %q", data, want)
+	}
+	if got.ContentType != "video/mp4" || got.Metadata["speaker"] != "alice" {
+		t.Errorf("Get() = %+v, want the content type and metadata of the upload", got)
+	}
+}
+
+func TestPresignMultipartUpload_noOverwrite(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "taken.bin", []byte("original"))
+
+	upload, err := client.PresignMultipartUpload(ctx, "taken.bin", 1, time.Hour)
+	if err != nil {
+		t.Fatalf("PresignMultipartUpload() failed: %v", err)
+	}
+	etag := putPart(t, upload.PartURLs[0], "replacement")
+
+	_, err = client.CompleteMultipartUpload(ctx, upload.Key, upload.UploadID, []CompletedPart{{PartNumber: 1, ETag: etag}}, WithNoOverwrite())
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("CompleteMultipartUpload() error = %v, want ErrPreconditionFailed", err)
+	}
+}
+
+func TestPresignMultipartUpload_abort(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	upload, err := client.PresignMultipartUpload(ctx, "abandoned.bin", 2, time.Hour)
+	if err != nil {
+		t.Fatalf("PresignMultipartUpload() failed: %v", err)
+	}
+	etag := putPart(t, upload.PartURLs[0], "some data")
+
+	if err := client.AbortMultipartUpload(ctx, upload.Key, upload.UploadID); err != nil {
+		t.Fatalf("AbortMultipartUpload() failed: %v", err)
+	}
+
+	if _, err := client.CompleteMultipartUpload(ctx, upload.Key, upload.UploadID, []CompletedPart{{PartNumber: 1, ETag: etag}}); err == nil {
+		t.Error("CompleteMultipartUpload() after abort succeeded, want an error")
+	}
+	if _, err := client.Head(ctx, "abandoned.bin"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Head() error = %v, want ErrObjectNotFound", err)
+	}
+}
+
+func TestPresignMultipartUpload_errors(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	tests := []struct {
+		name        string
+		key         string
+		partCount   int
+		expiry      time.Duration
+		errContains string
+	}{
+		{name: "empty key", key: "", partCount: 1, expiry: time.Hour, errContains: "key cannot be empty"},
+		{name: "no parts", key: "file", partCount: 0, expiry: time.Hour, errContains: "invalid part count"},
+		{name: "too many parts", key: "file", partCount: MaxParts + 1, expiry: time.Hour, errContains: "invalid part count"},
+		{name: "non-positive expiry", key: "file", partCount: 1, expiry: 0, errContains: "invalid expiry duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.PresignMultipartUpload(ctx, tt.key, tt.partCount, tt.expiry)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("PresignMultipartUpload() error = %v, want one containing %q", err, tt.errContains)
+			}
+		})
+	}
+
+	if _, err := client.CompleteMultipartUpload(ctx, "file", "upload", nil); err == nil || !strings.Contains(err.Error(), "no parts") {
+		t.Errorf("CompleteMultipartUpload() with no parts error = %v, want one containing %q", err, "no parts")
+	}
+}
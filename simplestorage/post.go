@@ -0,0 +1,161 @@
+package simplestorage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// PresignedPost is a signed POST policy for uploading an object from an HTML form.
+//
+// Send a multipart/form-data POST request to URL with every entry of Fields as a form
+// field, followed by the file itself in a field named "file". Fields after the file
+// are ignored, and fields not covered by the policy are rejected.
+type PresignedPost struct {
+	// URL is the bucket URL the form is posted to.
+	URL string
+
+	// Fields are the form fields the upload must include, among them the key, the
+	// policy and its signature.
+	Fields map[string]string
+
+	// Expires is the time after which the policy is no longer accepted.
+	Expires time.Time
+}
+
+// PostCondition is a condition that a form upload signed by PresignPost must satisfy.
+type PostCondition func(*postPolicy)
+
+// postPolicy collects the conditions of a POST policy document and the form fields
+// that satisfy them.
+type postPolicy struct {
+	conditions []any
+	fields     map[string]string
+}
+
+// eq requires form field to be value and sets it in the form fields.
+func (p *postPolicy) eq(field, value string) {
+	p.conditions = append(p.conditions, map[string]string{field: value})
+	p.fields[field] = value
+}
+
+// PostContentLengthRange limits the size of the uploaded file to between min and
+// max bytes inclusive.
+func PostContentLengthRange(min, max int64) PostCondition {
+	return func(p *postPolicy) {
+		p.conditions = append(p.conditions, []any{"content-length-range", min, max})
+	}
+}
+
+// PostContentType sets the Content-Type of the uploaded object and forbids the form
+// from changing it.
+func PostContentType(contentType string) PostCondition {
+	return func(p *postPolicy) {
+		p.eq("Content-Type", contentType)
+	}
+}
+
+// PostContentTypePrefix allows any Content-Type starting with prefix, such as
+// "image/". The form must send the Content-Type field itself.
+func PostContentTypePrefix(prefix string) PostCondition {
+	return func(p *postPolicy) {
+		p.conditions = append(p.conditions, []any{"starts-with", "$Content-Type", prefix})
+	}
+}
+
+// PostContentDisposition sets the Content-Disposition of the uploaded object.
+func PostContentDisposition(disposition string) PostCondition {
+	return func(p *postPolicy) {
+		p.eq("Content-Disposition", disposition)
+	}
+}
+
+// PostSuccessRedirect redirects the browser to redirectURL after a successful upload.
+// Tigris appends the bucket, key and etag of the new object to its query.
+func PostSuccessRedirect(redirectURL string) PostCondition {
+	return func(p *postPolicy) {
+		p.eq("success_action_redirect", redirectURL)
+	}
+}
+
+// PostMetadata sets user metadata (x-amz-meta-*) on the uploaded object.
+func PostMetadata(metadata map[string]string) PostCondition {
+	return func(p *postPolicy) {
+		for k, v := range metadata {
+			p.eq("x-amz-meta-"+strings.ToLower(k), v)
+		}
+	}
+}
+
+// WithPostConditions adds conditions that form uploads signed by PresignPost must
+// satisfy.
+func WithPostConditions(conditions ...PostCondition) ClientOption {
+	return func(co *ClientOptions) {
+		co.PostConditions = append(slices.Clip(co.PostConditions), conditions...)
+	}
+}
+
+// PresignPost signs a POST policy that lets a browser upload an object straight to
+// Tigris with an HTML form, without holding any credentials.
+//
+// If keyOrPrefix ends in "/", the form may upload any key under that prefix, and the
+// key defaults to the prefix followed by the name of the uploaded file. Otherwise the
+// upload is restricted to exactly that key. The policy expires after expiry. Use
+// WithPostConditions to restrict the upload further.
+//
+// The policy is signed locally with the client's credentials; nothing is sent to
+// Tigris until the form is submitted.
+func (c *Client) PresignPost(ctx context.Context, keyOrPrefix string, expiry time.Duration, opts ...ClientOption) (*PresignedPost, error) {
+	if keyOrPrefix == "" {
+		return nil, fmt.Errorf("simplestorage: key cannot be empty for presigned POST")
+	}
+
+	if expiry <= 0 {
+		return nil, fmt.Errorf("simplestorage: invalid expiry duration %v for presigned POST (must be positive)", expiry)
+	}
+
+	o := new(ClientOptions).defaults(c.options)
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	p := &postPolicy{fields: map[string]string{}}
+
+	key := keyOrPrefix
+	if strings.HasSuffix(keyOrPrefix, "/") {
+		p.conditions = append(p.conditions, []any{"starts-with", "$key", keyOrPrefix})
+		key = keyOrPrefix + "${filename}"
+	}
+
+	for _, cond := range o.PostConditions {
+		cond(p)
+	}
+
+	expires := time.Now().Add(expiry)
+
+	req, err := s3.NewPresignClient(c.cli.Client).PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(o.BucketName),
+		Key:    aws.String(key),
+	}, func(po *s3.PresignPostOptions) {
+		po.ClientOptions = o.S3Options
+		po.Expires = expiry
+		po.Conditions = p.conditions
+	})
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't presign POST to %s/%s: %w", o.BucketName, keyOrPrefix, err)
+	}
+
+	maps.Copy(req.Values, p.fields)
+
+	return &PresignedPost{
+		URL:     req.URL,
+		Fields:  req.Values,
+		Expires: expires,
+	}, nil
+}
@@ -0,0 +1,270 @@
+package simplestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postForm uploads data as filename with the fields of post plus extra, like a
+// browser submitting an HTML form.
+func postForm(t *testing.T, post *PresignedPost, extra map[string]string, filename, data string) *http.Response {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, fields := range []map[string]string{post.Fields, extra} {
+		for k, v := range fields {
+			mw.WriteField(k, v)
+		}
+	}
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(fw, data)
+	mw.Close()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Post(post.URL, mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", post.URL, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func TestPresignPost(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		keyOrPrefix string
+		conditions  []PostCondition
+		extra       map[string]string
+		filename    string
+		data        string
+		wantStatus  int
+		wantKey     string
+	}{
+		{
+			name:        "exact key",
+			keyOrPrefix: "uploads/avatar.png",
+			conditions:  []PostCondition{PostContentType("image/png"), PostContentLengthRange(1, 100)},
+			filename:    "me.png",
+			data:        "png data",
+			wantStatus:  http.StatusNoContent,
+			wantKey:     "uploads/avatar.png",
+		},
+		{
+			name:        "prefix with file name",
+			keyOrPrefix: "incoming/",
+			filename:    "notes.txt",
+			data:        "some notes",
+			wantStatus:  http.StatusNoContent,
+			wantKey:     "incoming/notes.txt",
+		},
+		{
+			name:        "prefix with chosen key",
+			keyOrPrefix: "incoming/",
+			extra:       map[string]string{"key": "incoming/chosen.txt"},
+			filename:    "ignored.txt",
+			data:        "chosen",
+			wantStatus:  http.StatusNoContent,
+			wantKey:     "incoming/chosen.txt",
+		},
+		{
+			name:        "content type prefix",
+			keyOrPrefix: "images/",
+			conditions:  []PostCondition{PostContentTypePrefix("image/")},
+			extra:       map[string]string{"Content-Type": "image/jpeg"},
+			filename:    "cat.jpg",
+			data:        "jpeg data",
+			wantStatus:  http.StatusNoContent,
+			wantKey:     "images/cat.jpg",
+		},
+		{
+			name:        "key outside prefix",
+			keyOrPrefix: "incoming/",
+			extra:       map[string]string{"key": "elsewhere/evil.txt"},
+			filename:    "evil.txt",
+			data:        "evil",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "too large",
+			keyOrPrefix: "uploads/big.bin",
+			conditions:  []PostCondition{PostContentLengthRange(1, 4)},
+			filename:    "big.bin",
+			data:        "too much data",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "too small",
+			keyOrPrefix: "uploads/empty.bin",
+			conditions:  []PostCondition{PostContentLengthRange(1, 4)},
+			filename:    "empty.bin",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "wrong content type",
+			keyOrPrefix: "images/",
+			conditions:  []PostCondition{PostContentTypePrefix("image/")},
+			extra:       map[string]string{"Content-Type": "text/html"},
+			filename:    "page.html",
+			data:        "<script>",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "extra field",
+			keyOrPrefix: "uploads/file.txt",
+			extra:       map[string]string{"x-amz-meta-owner": "mallory"},
+			filename:    "file.txt",
+			data:        "data",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "ignored field",
+			keyOrPrefix: "uploads/ignored.txt",
+			extra:       map[string]string{"x-ignore-csrf": "token"},
+			filename:    "ignored.txt",
+			data:        "data",
+			wantStatus:  http.StatusNoContent,
+			wantKey:     "uploads/ignored.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post, err := client.PresignPost(ctx, tt.keyOrPrefix, time.Hour, WithPostConditions(tt.conditions...))
+			if err != nil {
+				t.Fatalf("PresignPost() failed: %v", err)
+			}
+
+			resp := postForm(t, post, tt.extra, tt.filename, tt.data)
+			if resp.StatusCode != tt.wantStatus {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("POST status = %d, want %d (body %q)", resp.StatusCode, tt.wantStatus, body)
+			}
+
+			if tt.wantKey == "" {
+				return
+			}
+
+			obj, err := client.Get(ctx, tt.wantKey)
+			if err != nil {
+				t.Fatalf("Get(%q) failed: %v", tt.wantKey, err)
+			}
+			defer obj.Body.Close()
+
+			data, _ := io.ReadAll(obj.Body)
+			if string(data) != tt.data {
+				t.Errorf("Get(%q) = %q, want %q", tt.wantKey, data, tt.data)
+			}
+		})
+	}
+}
+
+func TestPresignPost_metadataAndRedirect(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	post, err := client.PresignPost(ctx, "docs/report.pdf", time.Hour, WithPostConditions(
+		PostContentType("application/pdf"),
+		PostContentDisposition(`attachment; filename="report.pdf"`),
+		PostMetadata(map[string]string{"Owner": "alice"}),
+		PostSuccessRedirect("https://example.com/done?from=form"),
+	))
+	if err != nil {
+		t.Fatalf("PresignPost() failed: %v", err)
+	}
+
+	if want := "https://example.com/done?from=form"; post.Fields["success_action_redirect"] != want {
+		t.Errorf("success_action_redirect = %q, want %q", post.Fields["success_action_redirect"], want)
+	}
+
+	resp := postForm(t, post, nil, "report.pdf", "%PDF")
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("POST status = %d, want 303", resp.StatusCode)
+	}
+	if loc := resp.Header.Get("Location"); !strings.HasPrefix(loc, "https://example.com/done?") || !strings.Contains(loc, "key=docs%2Freport.pdf") {
+		t.Errorf("Location = %q, want the redirect URL with the key", loc)
+	}
+
+	obj, err := client.Head(ctx, "docs/report.pdf")
+	if err != nil {
+		t.Fatalf("Head() failed: %v", err)
+	}
+	if obj.ContentType != "application/pdf" {
+		t.Errorf("ContentType = %q, want application/pdf", obj.ContentType)
+	}
+	if obj.Metadata["owner"] != "alice" {
+		t.Errorf("Metadata = %v, want owner=alice", obj.Metadata)
+	}
+}
+
+func TestPresignPost_expired(t *testing.T) {
+	client := newTestClient(t)
+
+	post, err := client.PresignPost(context.Background(), "late.txt", time.Millisecond)
+	if err != nil {
+		t.Fatalf("PresignPost() failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if resp := postForm(t, post, nil, "late.txt", "late"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("POST status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestPresignPost_errors(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.PresignPost(ctx, "", time.Hour); err == nil || !strings.Contains(err.Error(), "key cannot be empty") {
+		t.Errorf("PresignPost(\"\") error = %v, want empty key error", err)
+	}
+	if _, err := client.PresignPost(ctx, "file.txt", 0); err == nil || !strings.Contains(err.Error(), "invalid expiry duration") {
+		t.Errorf("PresignPost(0) error = %v, want invalid expiry error", err)
+	}
+}
+
+func TestPresignPost_overrideBucket(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.CreateBucket(ctx, "avatars"); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	post, err := client.PresignPost(ctx, "users/42.png", time.Hour,
+		OverrideBucket("avatars"),
+		WithPostConditions(PostContentType("image/png")),
+	)
+	if err != nil {
+		t.Fatalf("PresignPost() failed: %v", err)
+	}
+	if !strings.HasSuffix(post.URL, "/avatars") {
+		t.Errorf("URL = %s, want the avatars bucket", post.URL)
+	}
+
+	if resp := postForm(t, post, nil, "me.png", "png data"); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("POST status = %d, want 204", resp.StatusCode)
+	}
+	if _, err := client.For("avatars").Head(ctx, "users/42.png"); err != nil {
+		t.Errorf("Head() in the overridden bucket failed: %v", err)
+	}
+	if _, err := client.Head(ctx, "users/42.png"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Head() in the default bucket error = %v, want ErrObjectNotFound", err)
+	}
+}
@@ -52,6 +52,54 @@ func ExampleClient_PresignURL_put() {
 	fmt.Println("Presigned PUT URL:", url)
 }
 
+func ExampleClient_PresignURL_download() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Generate a URL that downloads the object under a friendly file name
+	url, err := client.PresignURL(ctx, http.MethodGet, "exports/3f9c2a.csv", time.Hour,
+		simplestorage.WithResponseContentDisposition(`attachment; filename="Q3 report.csv"`),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	fmt.Println("Presigned download URL:", url)
+}
+
+func ExampleClient_PresignRequest() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Sign the metadata into the URL and refuse to overwrite an existing object
+	req, err := client.PresignRequest(ctx, http.MethodPut, "uploads/avatar.png", 15*time.Minute,
+		simplestorage.WithContentType("image/png"),
+		simplestorage.WithMetadata(map[string]string{"user-id": "42"}),
+		simplestorage.WithNoOverwrite(),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	// The uploader must send every signed header with the request
+	fmt.Println(req.Method, req.URL)
+	for name, values := range req.Header {
+		fmt.Printf("%s: %s\n", name, values[0])
+	}
+}
+
 func ExampleClient_PresignURL_delete() {
 	ctx := context.Background()
 
@@ -70,3 +118,66 @@ func ExampleClient_PresignURL_delete() {
 
 	fmt.Println("Presigned DELETE URL:", url)
 }
+
+func ExampleClient_PresignPost() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Let a browser upload images of up to 10 MiB under avatars/ for the next 15 minutes
+	post, err := client.PresignPost(ctx, "avatars/", 15*time.Minute, simplestorage.WithPostConditions(
+		simplestorage.PostContentLengthRange(1, 10<<20),
+		simplestorage.PostContentTypePrefix("image/"),
+		simplestorage.PostSuccessRedirect("https://example.com/profile"),
+	))
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	// Render an HTML form that posts to post.URL with post.Fields as hidden inputs,
+	// a Content-Type input, and the file input named "file" last
+	fmt.Println("Form action:", post.URL)
+	for name, value := range post.Fields {
+		fmt.Printf("<input type=\"hidden\" name=%q value=%q>\n", name, value)
+	}
+}
+
+func ExampleClient_PresignMultipartUpload() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start an upload of a 2 GiB video in 64 MiB parts
+	upload, err := client.PresignMultipartUpload(ctx, "videos/keynote.mp4", 32, 6*time.Hour,
+		simplestorage.WithContentType("video/mp4"),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	// Hand upload.UploadID and upload.PartURLs to the browser, which PUTs each part
+	// and reports back the ETag response header of each one
+	parts := []simplestorage.CompletedPart{
+		{PartNumber: 1, ETag: `"etag-reported-by-the-browser"`},
+		// ...
+	}
+
+	obj, err := client.CompleteMultipartUpload(ctx, upload.Key, upload.UploadID, parts)
+	if err != nil {
+		// Give up on the upload so its parts don't linger
+		client.AbortMultipartUpload(ctx, upload.Key, upload.UploadID)
+		log.Fatal(err) // handle the error here
+	}
+
+	fmt.Println("Uploaded", obj.Key, obj.Etag)
+}
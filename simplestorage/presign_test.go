@@ -2,10 +2,15 @@ package simplestorage
 
 import (
 	"context"
+	"crypto/md5"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/tigrisdata/storage-go/tigrisheaders"
 )
 
 func TestPresignURL(t *testing.T) {
@@ -36,6 +41,12 @@ func TestPresignURL(t *testing.T) {
 			key:    "test/delete.txt",
 			expiry: 15 * time.Minute,
 		},
+		{
+			name:   "HEAD method succeeds",
+			method: http.MethodHead,
+			key:    "test/file.txt",
+			expiry: 15 * time.Minute,
+		},
 		{
 			name:        "unsupported method fails",
 			method:      "POST",
@@ -103,3 +114,130 @@ func TestPresignURL(t *testing.T) {
 		})
 	}
 }
+
+// sendPresigned sends req with body and the signed headers.
+func sendPresigned(t *testing.T, req *PresignedRequest, body string, header http.Header) *http.Response {
+	t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequest(req.Method, req.URL, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range req.Header {
+		httpReq.Header[k] = v
+	}
+	for k, v := range header {
+		httpReq.Header[k] = v
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", req.Method, req.URL, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func TestPresignRequest(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	data := "quarterly numbers"
+	sum := md5.Sum([]byte(data))
+
+	put, err := client.PresignRequest(ctx, http.MethodPut, "reports/q3.csv", time.Hour,
+		WithContentType("text/csv"),
+		WithCacheControl("max-age=60"),
+		WithMetadata(map[string]string{"owner": "alice"}),
+		WithContentMD5(sum[:]),
+		WithReplicationRegions(tigrisheaders.FRA, tigrisheaders.IAD),
+		WithNoOverwrite(),
+	)
+	if err != nil {
+		t.Fatalf("PresignRequest(PUT) failed: %v", err)
+	}
+
+	for k, want := range map[string]string{
+		"Content-Type":     "text/csv",
+		"Cache-Control":    "max-age=60",
+		"X-Amz-Meta-Owner": "alice",
+		"Content-Md5":      "",
+		"X-Tigris-Regions": "fra,iad",
+		"If-Match":         `""`,
+	} {
+		got := put.Header.Get(k)
+		if got == "" || (want != "" && got != want) {
+			t.Errorf("signed header %s = %q, want %q", k, got, want)
+		}
+	}
+	if put.Header.Get("Host") != "" {
+		t.Errorf("signed headers include Host")
+	}
+
+	u, err := url.Parse(put.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed := u.Query().Get("X-Amz-SignedHeaders"); !strings.Contains(signed, "x-amz-meta-owner") || !strings.Contains(signed, "if-match") {
+		t.Errorf("X-Amz-SignedHeaders = %q, want metadata and If-Match signed", signed)
+	}
+
+	if resp := sendPresigned(t, put, "tampered", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT with a different body status = %d, want 400", resp.StatusCode)
+	}
+	if resp := sendPresigned(t, put, data, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200", resp.StatusCode)
+	}
+	if resp := sendPresigned(t, put, data, nil); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("second PUT status = %d, want 412", resp.StatusCode)
+	}
+
+	obj, err := client.Head(ctx, "reports/q3.csv")
+	if err != nil {
+		t.Fatalf("Head() failed: %v", err)
+	}
+	if obj.ContentType != "text/csv" || obj.Metadata["owner"] != "alice" {
+		t.Errorf("uploaded object = %+v, want the signed headers applied", obj)
+	}
+
+	get, err := client.PresignRequest(ctx, http.MethodGet, "reports/q3.csv", time.Hour,
+		WithResponseContentDisposition(`attachment; filename="Q3 report.csv"`),
+		WithResponseContentType("application/octet-stream"),
+		WithResponseCacheControl("no-store"),
+	)
+	if err != nil {
+		t.Fatalf("PresignRequest(GET) failed: %v", err)
+	}
+	if len(get.Header) != 0 {
+		t.Errorf("GET signed headers = %v, want none", get.Header)
+	}
+
+	resp := sendPresigned(t, get, "", nil)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != data {
+		t.Fatalf("GET = %d %q, want 200 %q", resp.StatusCode, body, data)
+	}
+	for k, want := range map[string]string{
+		"Content-Disposition": `attachment; filename="Q3 report.csv"`,
+		"Content-Type":        "application/octet-stream",
+		"Cache-Control":       "no-store",
+	} {
+		if got := resp.Header.Get(k); got != want {
+			t.Errorf("GET response header %s = %q, want %q", k, got, want)
+		}
+	}
+
+	head, err := client.PresignRequest(ctx, http.MethodHead, "reports/q3.csv", time.Hour)
+	if err != nil {
+		t.Fatalf("PresignRequest(HEAD) failed: %v", err)
+	}
+	if resp := sendPresigned(t, head, "", nil); resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") != obj.Etag {
+		t.Errorf("HEAD = %d with ETag %q, want 200 with %q", resp.StatusCode, resp.Header.Get("ETag"), obj.Etag)
+	}
+}
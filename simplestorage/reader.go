@@ -0,0 +1,342 @@
+package simplestorage
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+)
+
+// ObjectReader gives random access to an object in Tigris. It implements io.ReaderAt,
+// io.ReadSeeker and io.Closer, so it can be handed to readers of formats that seek,
+// such as archive/zip or Parquet footers.
+//
+// Every read is a ranged GET that is conditional on the ETag the object had when it
+// was opened. If the object is overwritten while the reader is open, reads fail with
+// an error wrapping ErrObjectChanged instead of mixing bytes from different versions.
+//
+// ReadAt may be called concurrently. Read and Seek share an offset and must not be
+// called concurrently with each other.
+type ObjectReader struct {
+	c   *Client
+	ctx context.Context
+	o   ClientOptions
+	obj Object
+
+	closed atomic.Bool
+
+	// mu guards the sequential read state.
+	mu      sync.Mutex
+	pos     int64
+	body    io.ReadCloser
+	bodyPos int64
+
+	cache *blockCache // nil unless WithBlockCache was used
+}
+
+// Open looks up an object and returns a reader for random access to its contents.
+//
+// ctx is used for all requests made by the reader, so it must stay valid until the
+// reader is closed. Use WithBlockCache to fetch and cache whole blocks instead of
+// issuing a request for every read.
+func (c *Client) Open(ctx context.Context, key string, opts ...ClientOption) (*ObjectReader, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	obj, err := c.headObject(ctx, key, o)
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't open %s/%s: %w", o.BucketName, key, err)
+	}
+
+	r := &ObjectReader{
+		c:   c,
+		ctx: ctx,
+		o:   o,
+		obj: *obj,
+	}
+
+	if o.BlockSize > 0 && o.CacheBlocks > 0 {
+		r.cache = newBlockCache(o.BlockSize, o.CacheBlocks)
+	}
+
+	return r, nil
+}
+
+// Info returns the metadata of the object as it was when the reader was opened. The
+// returned Object has no Body.
+func (r *ObjectReader) Info() *Object {
+	obj := r.obj
+	return &obj
+}
+
+// Size returns the size of the object in bytes.
+func (r *ObjectReader) Size() int64 {
+	return r.obj.Size
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *ObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	if r.closed.Load() {
+		return 0, fs.ErrClosed
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("simplestorage: can't read %s/%s: negative offset %d", r.obj.Bucket, r.obj.Key, off)
+	}
+	if off >= r.obj.Size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	want := min(int64(len(p)), r.obj.Size-off)
+
+	var (
+		n   int
+		err error
+	)
+	if r.cache != nil {
+		n, err = r.readCached(p[:want], off)
+	} else {
+		n, err = r.readRange(p[:want], off)
+	}
+	if err != nil {
+		return n, fmt.Errorf("simplestorage: can't read %s/%s: %w", r.obj.Bucket, r.obj.Key, err)
+	}
+
+	if want < int64(len(p)) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readRange fills p with the bytes at off using a single ranged GET.
+func (r *ObjectReader) readRange(p []byte, off int64) (int, error) {
+	end := off + int64(len(p)) - 1
+
+	body, err := r.c.getRange(r.ctx, r.obj.Key, r.obj.Etag, off, end, r.o)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err := io.ReadFull(body, p)
+	if err != nil {
+		return n, fmt.Errorf("reading bytes %d-%d: %w", off, end, err)
+	}
+
+	return n, nil
+}
+
+// readCached fills p with the bytes at off from cached blocks, fetching the blocks
+// that are missing.
+func (r *ObjectReader) readCached(p []byte, off int64) (int, error) {
+	bs := r.cache.blockSize
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		idx := pos / bs
+
+		block, ok := r.cache.get(idx)
+		if !ok {
+			start := idx * bs
+			block = make([]byte, min(bs, r.obj.Size-start))
+			if _, err := r.readRange(block, start); err != nil {
+				return n, err
+			}
+			r.cache.add(idx, block)
+		}
+
+		n += copy(p[n:], block[pos-idx*bs:])
+	}
+
+	return n, nil
+}
+
+// Read implements io.Reader. Without a block cache, sequential reads share one
+// streaming GET that is restarted after each Seek.
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed.Load() {
+		return 0, fs.ErrClosed
+	}
+	if r.pos >= r.obj.Size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if r.cache != nil {
+		n, err := r.ReadAt(p, r.pos)
+		r.pos += int64(n)
+		if err == io.EOF && n > 0 {
+			err = nil
+		}
+		return n, err
+	}
+
+	if r.body != nil && r.bodyPos != r.pos {
+		r.body.Close()
+		r.body = nil
+	}
+	if r.body == nil {
+		body, err := r.c.getRange(r.ctx, r.obj.Key, r.obj.Etag, r.pos, -1, r.o)
+		if err != nil {
+			return 0, fmt.Errorf("simplestorage: can't read %s/%s: %w", r.obj.Bucket, r.obj.Key, err)
+		}
+		r.body = body
+		r.bodyPos = r.pos
+	}
+
+	n, err := r.body.Read(p)
+	r.pos += int64(n)
+	r.bodyPos = r.pos
+
+	if err != nil {
+		r.body.Close()
+		r.body = nil
+
+		if err != io.EOF {
+			return n, fmt.Errorf("simplestorage: can't read %s/%s: %w", r.obj.Bucket, r.obj.Key, err)
+		}
+		if r.pos < r.obj.Size {
+			return n, fmt.Errorf("simplestorage: can't read %s/%s: %w", r.obj.Bucket, r.obj.Key, io.ErrUnexpectedEOF)
+		}
+	}
+
+	return n, nil
+}
+
+// Seek implements io.Seeker. Seeking past the end of the object is allowed; reads
+// from there return io.EOF.
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed.Load() {
+		return 0, fs.ErrClosed
+	}
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.obj.Size
+	default:
+		return 0, fmt.Errorf("simplestorage: can't seek %s/%s: invalid whence %d", r.obj.Bucket, r.obj.Key, whence)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("simplestorage: can't seek %s/%s: negative position %d", r.obj.Bucket, r.obj.Key, offset)
+	}
+
+	r.pos = offset
+	return offset, nil
+}
+
+// Close releases the reader's open connection and cached blocks. Reads after Close
+// fail with fs.ErrClosed.
+func (r *ObjectReader) Close() error {
+	if r.closed.Swap(true) {
+		return fs.ErrClosed
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cache != nil {
+		r.cache.reset()
+	}
+
+	if r.body != nil {
+		err := r.body.Close()
+		r.body = nil
+		return err
+	}
+
+	return nil
+}
+
+// blockCache is a fixed-size LRU cache of object blocks.
+type blockCache struct {
+	blockSize int64
+	capacity  int
+
+	mu      sync.Mutex
+	order   *list.List // of *cachedBlock, most recently used first
+	entries map[int64]*list.Element
+}
+
+// cachedBlock is a block of an object starting at index*blockSize.
+type cachedBlock struct {
+	index int64
+	data  []byte
+}
+
+func newBlockCache(blockSize int64, capacity int) *blockCache {
+	return &blockCache{
+		blockSize: blockSize,
+		capacity:  capacity,
+		order:     list.New(),
+		entries:   map[int64]*list.Element{},
+	}
+}
+
+// get returns the block with the given index, if cached.
+func (bc *blockCache) get(index int64) ([]byte, bool) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	e, ok := bc.entries[index]
+	if !ok {
+		return nil, false
+	}
+
+	bc.order.MoveToFront(e)
+	return e.Value.(*cachedBlock).data, true
+}
+
+// add stores a block, evicting the least recently used block if the cache is full.
+func (bc *blockCache) add(index int64, data []byte) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if e, ok := bc.entries[index]; ok {
+		bc.order.MoveToFront(e)
+		return
+	}
+
+	bc.entries[index] = bc.order.PushFront(&cachedBlock{index: index, data: data})
+
+	if bc.order.Len() > bc.capacity {
+		oldest := bc.order.Back()
+		bc.order.Remove(oldest)
+		delete(bc.entries, oldest.Value.(*cachedBlock).index)
+	}
+}
+
+// reset drops all cached blocks.
+func (bc *blockCache) reset() {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.order.Init()
+	clear(bc.entries)
+}
+
+var (
+	_ io.ReaderAt   = (*ObjectReader)(nil)
+	_ io.ReadSeeker = (*ObjectReader)(nil)
+	_ io.Closer     = (*ObjectReader)(nil)
+)
@@ -0,0 +1,233 @@
+package simplestorage
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+)
+
+func putTestObject(t *testing.T, client *Client, key string, data []byte) {
+	t.Helper()
+
+	if _, err := client.Put(context.Background(), &Object{
+		Key:  key,
+		Size: int64(len(data)),
+		Body: seekableBody(string(data)),
+	}); err != nil {
+		t.Fatalf("Put(%q) failed: %v", key, err)
+	}
+}
+
+func TestObjectReader_ReadAt(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	tests := []struct {
+		name    string
+		off     int64
+		n       int
+		want    string
+		wantEOF bool
+		wantErr bool
+	}{
+		{name: "start", off: 0, n: 4, want: "0123"},
+		{name: "middle across blocks", off: 6, n: 10, want: "6789abcdef"},
+		{name: "up to the end", off: 30, n: 6, want: "uvwxyz"},
+		{name: "past the end", off: 30, n: 10, want: "uvwxyz", wantEOF: true},
+		{name: "at the end", off: 36, n: 4, want: "", wantEOF: true},
+		{name: "negative offset", off: -1, n: 4, wantErr: true},
+	}
+
+	for _, cached := range []bool{false, true} {
+		client := newTestClient(t)
+		putTestObject(t, client, "data.bin", data)
+
+		var opts []ClientOption
+		if cached {
+			opts = append(opts, WithBlockCache(8, 2))
+		}
+
+		r, err := client.Open(context.Background(), "data.bin", opts...)
+		if err != nil {
+			t.Fatalf("Open() failed: %v", err)
+		}
+		defer r.Close()
+
+		if r.Size() != int64(len(data)) {
+			t.Errorf("Size() = %d, want %d", r.Size(), len(data))
+		}
+
+		for _, tt := range tests {
+			name := tt.name
+			if cached {
+				name += " cached"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				buf := make([]byte, tt.n)
+				n, err := r.ReadAt(buf, tt.off)
+
+				switch {
+				case tt.wantErr:
+					if err == nil || err == io.EOF {
+						t.Fatalf("ReadAt() error = %v, want error", err)
+					}
+					return
+				case tt.wantEOF:
+					if err != io.EOF {
+						t.Fatalf("ReadAt() error = %v, want io.EOF", err)
+					}
+				case err != nil:
+					t.Fatalf("ReadAt() failed: %v", err)
+				}
+
+				if got := string(buf[:n]); got != tt.want {
+					t.Errorf("ReadAt() = %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestObjectReader_ReadSeek(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "data.bin", []byte("hello, tigris world"))
+
+	r, err := client.Open(ctx, "data.bin")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer r.Close()
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(r, head); err != nil || string(head) != "hello" {
+		t.Fatalf("ReadFull() = %q, %v, want %q", head, err, "hello")
+	}
+
+	if pos, err := r.Seek(-5, io.SeekEnd); err != nil || pos != 14 {
+		t.Fatalf("Seek(-5, SeekEnd) = %d, %v, want 14", pos, err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil || string(rest) != "world" {
+		t.Fatalf("ReadAll() = %q, %v, want %q", rest, err, "world")
+	}
+
+	if pos, err := r.Seek(7, io.SeekStart); err != nil || pos != 7 {
+		t.Fatalf("Seek(7, SeekStart) = %d, %v, want 7", pos, err)
+	}
+	if pos, err := r.Seek(2, io.SeekCurrent); err != nil || pos != 9 {
+		t.Fatalf("Seek(2, SeekCurrent) = %d, %v, want 9", pos, err)
+	}
+	rest, err = io.ReadAll(r)
+	if err != nil || string(rest) != "gris world" {
+		t.Fatalf("ReadAll() = %q, %v, want %q", rest, err, "gris world")
+	}
+
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Error("Seek(-1, SeekStart) succeeded, want error")
+	}
+}
+
+func TestObjectReader_zip(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(fw, "contents of "+name)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	putTestObject(t, client, "archive.zip", buf.Bytes())
+
+	r, err := client.Open(ctx, "archive.zip", WithBlockCache(64, 4))
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer r.Close()
+
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		t.Fatalf("zip.NewReader() failed: %v", err)
+	}
+
+	fin, err := zr.Open("b.txt")
+	if err != nil {
+		t.Fatalf("Open(b.txt) failed: %v", err)
+	}
+	defer fin.Close()
+
+	got, err := io.ReadAll(fin)
+	if err != nil || string(got) != "contents of b.txt" {
+		t.Errorf("ReadAll(b.txt) = %q, %v, want %q", got, err, "contents of b.txt")
+	}
+}
+
+func TestObjectReader_objectChanged(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "data.bin", []byte("original contents"))
+
+	r, err := client.Open(ctx, "data.bin", WithBlockCache(8, 4))
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer r.Close()
+
+	buf := make([]byte, 4)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt() failed: %v", err)
+	}
+
+	putTestObject(t, client, "data.bin", []byte("replaced contents"))
+
+	// The first block is cached, so it is still served from the original object.
+	if _, err := r.ReadAt(buf, 0); err != nil || string(buf) != "orig" {
+		t.Errorf("ReadAt() of cached block = %q, %v, want %q", buf, err, "orig")
+	}
+
+	if _, err := r.ReadAt(buf, 12); !errors.Is(err, ErrObjectChanged) {
+		t.Errorf("ReadAt() error = %v, want ErrObjectChanged", err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrObjectChanged) {
+		t.Errorf("Read() error = %v, want ErrObjectChanged", err)
+	}
+}
+
+func TestObjectReader_Close(t *testing.T) {
+	client := newTestClient(t)
+	putTestObject(t, client, "data.bin", []byte("data"))
+
+	r, err := client.Open(context.Background(), "data.bin")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if _, err := r.ReadAt(make([]byte, 1), 0); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("ReadAt() after Close() error = %v, want fs.ErrClosed", err)
+	}
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("Read() after Close() error = %v, want fs.ErrClosed", err)
+	}
+}
+
+func TestClient_Open_notFound(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := client.Open(context.Background(), "missing"); err == nil {
+		t.Error("Open() of a missing object succeeded, want error")
+	}
+}
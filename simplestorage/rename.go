@@ -0,0 +1,186 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+// KeyError is the failure of one key in an operation on many objects.
+type KeyError struct {
+	Key string // Key the operation failed for
+	Err error  // Why it failed
+}
+
+// Error implements error.
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// RenameReport describes the outcome of RenamePrefix.
+type RenameReport struct {
+	Renamed map[string]string // Destination key of each renamed object, by source key
+	Failed  []*KeyError       // Source keys that could not be renamed, sorted by key
+}
+
+// Rename moves the object at src to dst within the bucket, using Tigris' in-place
+// rename so the data is not copied. With WithNoOverwrite, an existing object at dst is
+// left alone and Rename returns a *ConditionError matching ErrPreconditionFailed.
+//
+// The returned Object describes the object at dst and has no Body.
+func (c *Client) Rename(ctx context.Context, src, dst string, opts ...ClientOption) (*Object, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	obj, err := c.rename(ctx, src, dst, o)
+	if err != nil {
+		if o.NoOverwrite && errors.Is(err, ErrPreconditionFailed) {
+			err = c.conditionFailed(ctx, dst, err, opts)
+		}
+		return nil, fmt.Errorf("simplestorage: can't rename %s/%s to %s: %w", o.BucketName, src, dst, err)
+	}
+
+	return obj, nil
+}
+
+// rename renames src to dst, returning errors from Tigris unwrapped.
+func (c *Client) rename(ctx context.Context, src, dst string, o ClientOptions) (*Object, error) {
+	s3Opts := o.S3Options
+	if o.NoOverwrite {
+		s3Opts = append(slices.Clip(s3Opts), tigrisheaders.WithCreateObjectIfNotExists())
+	}
+
+	resp, err := c.cli.RenameObject(
+		ctx,
+		&s3.CopyObjectInput{
+			Bucket:     aws.String(o.BucketName),
+			CopySource: aws.String(copySource(o.BucketName, src, "")),
+			Key:        aws.String(dst),
+		},
+		s3Opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &Object{
+		Bucket:  o.BucketName,
+		Key:     dst,
+		Version: lower(resp.VersionId, ""),
+	}
+	if resp.CopyObjectResult != nil {
+		obj.Etag = lower(resp.CopyObjectResult.ETag, "")
+		obj.LastModified = lower(resp.CopyObjectResult.LastModified, obj.LastModified)
+	}
+
+	return obj, nil
+}
+
+// RenamePrefix moves every object whose key starts with srcPrefix to the same key
+// under dstPrefix, like moving a directory. Objects are renamed in place with up to
+// the configured number of concurrent requests (see WithConcurrency). With
+// WithNoOverwrite, objects whose destination already exists are not renamed and are
+// reported as failures matching ErrPreconditionFailed.
+//
+// The listing is taken before any object is renamed, so dstPrefix may lie under
+// srcPrefix. A failure to rename one object does not stop the others: the report lists
+// every renamed and failed key, and the returned error joins the failures. If the
+// listing fails or ctx is cancelled, RenamePrefix returns the objects renamed so far
+// and the error.
+func (c *Client) RenamePrefix(ctx context.Context, srcPrefix, dstPrefix string, opts ...ClientOption) (*RenameReport, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	report := &RenameReport{Renamed: map[string]string{}}
+
+	if srcPrefix == dstPrefix {
+		return report, nil
+	}
+
+	var keys []string
+	for obj, err := range c.ListAll(ctx, append(slices.Clip(opts), WithPrefix(srcPrefix))...) {
+		if err != nil {
+			return report, fmt.Errorf("simplestorage: can't rename %s/%s to %s: %w", o.BucketName, srcPrefix, dstPrefix, err)
+		}
+		keys = append(keys, obj.Key)
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	sem := make(chan struct{}, max(o.Concurrency, 1))
+
+	for _, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		dst := dstPrefix + strings.TrimPrefix(key, srcPrefix)
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			_, err := c.rename(ctx, key, dst, o)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Failed = append(report.Failed, &KeyError{Key: key, Err: err})
+				return
+			}
+			report.Renamed[key] = dst
+		})
+	}
+
+	wg.Wait()
+
+	slices.SortFunc(report.Failed, func(a, b *KeyError) int { return strings.Compare(a.Key, b.Key) })
+
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("simplestorage: can't rename %s/%s to %s: %w", o.BucketName, srcPrefix, dstPrefix, err)
+	}
+
+	if len(report.Failed) > 0 {
+		errs := make([]error, len(report.Failed))
+		for i, ke := range report.Failed {
+			errs[i] = ke
+		}
+		return report, fmt.Errorf("simplestorage: can't rename %d of %d objects from %s/%s to %s: %w", len(report.Failed), len(keys), o.BucketName, srcPrefix, dstPrefix, errors.Join(errs...))
+	}
+
+	return report, nil
+}
+
+// copySource returns the CopySource value for key in bucket, optionally at a given
+// version.
+func copySource(bucket, key, version string) string {
+	src := bucket + "/" + (&url.URL{Path: key}).EscapedPath()
+	if version != "" {
+		src += "?versionId=" + url.QueryEscape(version)
+	}
+	return src
+}
@@ -0,0 +1,54 @@
+package simplestorage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_Rename() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Renamed in place, without copying the data
+	if _, err := client.Rename(ctx, "uploads/tmp-1234", "uploads/report.pdf"); err != nil {
+		log.Fatal(err) // handle the error here
+	}
+}
+
+func ExampleClient_RenamePrefix() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Move a "directory", without replacing anything already processed
+	report, err := client.RenamePrefix(ctx, "incoming/batch-42/", "processed/batch-42/",
+		simplestorage.WithNoOverwrite(),
+	)
+	fmt.Println("Moved", len(report.Renamed), "objects")
+
+	for _, failed := range report.Failed {
+		if errors.Is(failed, simplestorage.ErrPreconditionFailed) {
+			fmt.Println("Already processed:", failed.Key)
+			continue
+		}
+		fmt.Println("Failed to move", failed.Key, failed.Err)
+	}
+	if err != nil && len(report.Failed) == 0 {
+		log.Fatal(err) // the listing failed
+	}
+}
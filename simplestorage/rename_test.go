@@ -0,0 +1,147 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func listKeys(t *testing.T, client *Client) []string {
+	t.Helper()
+
+	var keys []string
+	for obj, err := range client.ListAll(context.Background()) {
+		if err != nil {
+			t.Fatalf("ListAll() failed: %v", err)
+		}
+		keys = append(keys, obj.Key)
+	}
+	return keys
+}
+
+func TestClient_Rename(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "old name.txt", []byte("hello"))
+	putTestObject(t, client, "taken.txt", []byte("keep me"))
+
+	obj, err := client.Rename(ctx, "old name.txt", "new/name.txt")
+	if err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+	if obj.Key != "new/name.txt" || obj.Etag == "" {
+		t.Errorf("Rename() = key %q ETag %q, want key new/name.txt and an ETag", obj.Key, obj.Etag)
+	}
+	if got, want := listKeys(t, client), []string{"new/name.txt", "taken.txt"}; !slices.Equal(got, want) {
+		t.Errorf("keys after Rename() = %q, want %q", got, want)
+	}
+
+	if _, err := client.Rename(ctx, "missing.txt", "other.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Rename() of a missing object error = %v, want ErrObjectNotFound", err)
+	}
+
+	_, err = client.Rename(ctx, "new/name.txt", "taken.txt", WithNoOverwrite())
+	var ce *ConditionError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Rename() onto an existing object with WithNoOverwrite() error = %v, want *ConditionError", err)
+	}
+	if got := readTestObject(t, client, "taken.txt"); got != "keep me" {
+		t.Errorf("taken.txt = %q, want it unchanged", got)
+	}
+
+	if _, err := client.Rename(ctx, "new/name.txt", "taken.txt"); err != nil {
+		t.Fatalf("Rename() onto an existing object failed: %v", err)
+	}
+	if got := readTestObject(t, client, "taken.txt"); got != "hello" {
+		t.Errorf("taken.txt = %q, want hello", got)
+	}
+}
+
+func TestClient_RenamePrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []string
+		src, dst  string
+		opts      []ClientOption
+		wantKeys  []string
+		wantMoved map[string]string
+		wantFail  []string
+	}{
+		{
+			name:     "move directory",
+			existing: []string{"incoming/a.txt", "incoming/sub/b.txt", "incomingx.txt", "other/c.txt"},
+			src:      "incoming/",
+			dst:      "done/",
+			wantKeys: []string{"done/a.txt", "done/sub/b.txt", "incomingx.txt", "other/c.txt"},
+			wantMoved: map[string]string{
+				"incoming/a.txt":     "done/a.txt",
+				"incoming/sub/b.txt": "done/sub/b.txt",
+			},
+		},
+		{
+			name:     "destination under source",
+			existing: []string{"data/a", "data/b"},
+			src:      "data/",
+			dst:      "data/archive/",
+			wantKeys: []string{"data/archive/a", "data/archive/b"},
+			wantMoved: map[string]string{
+				"data/a": "data/archive/a",
+				"data/b": "data/archive/b",
+			},
+		},
+		{
+			name:     "no overwrite",
+			existing: []string{"src/a", "src/b", "dst/b"},
+			src:      "src/",
+			dst:      "dst/",
+			opts:     []ClientOption{WithNoOverwrite(), WithConcurrency(1)},
+			wantKeys: []string{"dst/a", "dst/b", "src/b"},
+			wantMoved: map[string]string{
+				"src/a": "dst/a",
+			},
+			wantFail: []string{"src/b"},
+		},
+		{
+			name:      "empty prefix",
+			existing:  []string{"x"},
+			src:       "nothing/",
+			dst:       "elsewhere/",
+			wantKeys:  []string{"x"},
+			wantMoved: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newTestClient(t)
+			for _, key := range tt.existing {
+				putTestObject(t, client, key, []byte(key))
+			}
+
+			report, err := client.RenamePrefix(ctx, tt.src, tt.dst, tt.opts...)
+			if len(tt.wantFail) == 0 && err != nil {
+				t.Fatalf("RenamePrefix() failed: %v", err)
+			}
+			if len(tt.wantFail) > 0 && !errors.Is(err, ErrPreconditionFailed) {
+				t.Fatalf("RenamePrefix() error = %v, want ErrPreconditionFailed", err)
+			}
+
+			if !maps.Equal(report.Renamed, tt.wantMoved) {
+				t.Errorf("Renamed = %v, want %v", report.Renamed, tt.wantMoved)
+			}
+			var failed []string
+			for _, ke := range report.Failed {
+				failed = append(failed, ke.Key)
+			}
+			if !slices.Equal(failed, tt.wantFail) {
+				t.Errorf("Failed = %q, want %q", failed, tt.wantFail)
+			}
+			if got := listKeys(t, client); !slices.Equal(got, tt.wantKeys) {
+				t.Errorf("keys = %q, want %q", got, tt.wantKeys)
+			}
+		})
+	}
+}
@@ -0,0 +1,562 @@
+package simplestorage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// SyncOp is the kind of change made by SyncUpload or SyncDownload.
+type SyncOp string
+
+const (
+	SyncUpload   SyncOp = "upload"   // A local file is uploaded to the bucket
+	SyncDownload SyncOp = "download" // An object is downloaded to a local file
+	SyncDelete   SyncOp = "delete"   // An object or local file is deleted (see WithMirror)
+)
+
+// SyncAction is one change made, or planned with WithDryRun, by SyncUpload or
+// SyncDownload.
+type SyncAction struct {
+	Op     SyncOp // What is done
+	Key    string // Key of the object
+	Path   string // Path of the local file
+	Size   int64  // Bytes to transfer, or 0 for deletes
+	Reason string // Why, such as "missing", "size differs" or "newer locally"
+}
+
+// String returns a one-line description of the action, for printing a plan.
+func (a SyncAction) String() string {
+	switch a.Op {
+	case SyncUpload:
+		return fmt.Sprintf("upload %s to %s (%s)", a.Path, a.Key, a.Reason)
+	case SyncDownload:
+		return fmt.Sprintf("download %s to %s (%s)", a.Key, a.Path, a.Reason)
+	case SyncDelete:
+		if a.Path != "" {
+			return fmt.Sprintf("delete %s (%s)", a.Path, a.Reason)
+		}
+		return fmt.Sprintf("delete %s (%s)", a.Key, a.Reason)
+	}
+	return fmt.Sprintf("%s %s %s (%s)", a.Op, a.Path, a.Key, a.Reason)
+}
+
+// SyncReport describes the outcome of SyncUpload or SyncDownload.
+type SyncReport struct {
+	Actions   []SyncAction // Changes made, or planned with WithDryRun, sorted by key
+	Unchanged int          // Files that were already up to date
+	Failed    []*KeyError  // Keys whose change failed, sorted by key
+}
+
+// syncFile is a file or object that takes part in a sync, by its path relative to
+// the directory or prefix.
+type syncFile struct {
+	size    int64
+	modTime time.Time
+	etag    string // Only set for objects
+}
+
+// SyncUpload makes the objects under prefix match the files in the local directory
+// dir, uploading only files that are missing from the bucket or have changed.
+//
+// A file has changed if its size differs from the object's, or if it was modified
+// after the object was last written. With WithChecksum, files of the same size are
+// compared by content instead, using the object's ETag, including the ETags of
+// multipart uploads. With WithMirror, objects under prefix that have no matching
+// file are deleted. WithInclude and WithExclude limit the files and objects that take
+// part. Up to the configured number of files are transferred at once (see
+// WithConcurrency).
+//
+// prefix is treated as a directory: a file at dir/a/b.txt becomes the object
+// prefix/a/b.txt. Symbolic links and other non-regular files are skipped.
+//
+// With WithDryRun, nothing is changed and the report lists the planned actions. A
+// failure to transfer one file does not stop the others: the report lists every
+// action and failure, and the returned error joins the failures.
+func (c *Client) SyncUpload(ctx context.Context, dir, prefix string, opts ...ClientOption) (*SyncReport, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	report, err := c.sync(ctx, dir, syncPrefix(prefix), true, o, opts)
+	if err != nil {
+		return report, fmt.Errorf("simplestorage: can't sync %s to %s/%s: %w", dir, o.BucketName, prefix, err)
+	}
+
+	return report, nil
+}
+
+// SyncDownload makes the files in the local directory dir match the objects under
+// prefix, downloading only objects that are missing locally or have changed. It is
+// the reverse of SyncUpload and takes the same options.
+//
+// An object has changed if its size differs from the file's, or if it was written
+// after the file was last modified. Downloaded files get the object's last modified
+// time, so unchanged objects are skipped next time. With WithMirror, files under dir
+// that have no matching object are deleted. Objects whose keys would escape dir,
+// such as keys containing "..", are reported as failures and not downloaded.
+func (c *Client) SyncDownload(ctx context.Context, prefix, dir string, opts ...ClientOption) (*SyncReport, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	report, err := c.sync(ctx, dir, syncPrefix(prefix), false, o, opts)
+	if err != nil {
+		return report, fmt.Errorf("simplestorage: can't sync %s/%s to %s: %w", o.BucketName, prefix, dir, err)
+	}
+
+	return report, nil
+}
+
+// syncPrefix returns prefix as a directory, ending in a slash unless it is empty.
+func syncPrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return prefix + "/"
+	}
+	return prefix
+}
+
+// sync plans and carries out a sync between dir and prefix in either direction.
+func (c *Client) sync(ctx context.Context, dir, prefix string, upload bool, o ClientOptions, opts []ClientOption) (*SyncReport, error) {
+	local, err := localFiles(dir, o)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := map[string]syncFile{}
+	for obj, err := range c.ListAll(ctx, append(slices.Clip(opts), WithPrefix(prefix))...) {
+		if err != nil {
+			return nil, err
+		}
+
+		rel := strings.TrimPrefix(obj.Key, prefix)
+		if rel == "" || strings.HasSuffix(rel, "/") || !syncIncluded(rel, o) {
+			continue
+		}
+		remote[rel] = syncFile{size: obj.Size, modTime: obj.LastModified, etag: obj.Etag}
+	}
+
+	src, dst := local, remote
+	if !upload {
+		src, dst = remote, local
+	}
+
+	report := &SyncReport{}
+
+	for _, rel := range slices.Sorted(maps.Keys(src)) {
+		action := SyncAction{
+			Op:   SyncUpload,
+			Key:  prefix + rel,
+			Path: filepath.Join(dir, filepath.FromSlash(rel)),
+			Size: src[rel].size,
+		}
+		if !upload {
+			action.Op = SyncDownload
+			if !filepath.IsLocal(filepath.FromSlash(rel)) {
+				report.Failed = append(report.Failed, &KeyError{Key: action.Key, Err: fmt.Errorf("key escapes %s", dir)})
+				continue
+			}
+		}
+
+		_, exists := dst[rel]
+		reason, err := syncReason(local[rel], remote[rel], exists, upload, action.Path, o)
+		if err != nil {
+			report.Failed = append(report.Failed, &KeyError{Key: action.Key, Err: err})
+			continue
+		}
+		if reason == "" {
+			report.Unchanged++
+			continue
+		}
+
+		action.Reason = reason
+		report.Actions = append(report.Actions, action)
+	}
+
+	if o.Mirror {
+		for _, rel := range slices.Sorted(maps.Keys(dst)) {
+			if _, ok := src[rel]; ok {
+				continue
+			}
+
+			action := SyncAction{Op: SyncDelete, Key: prefix + rel, Reason: "not in source"}
+			if !upload {
+				action.Path = filepath.Join(dir, filepath.FromSlash(rel))
+			}
+			report.Actions = append(report.Actions, action)
+		}
+	}
+
+	slices.SortFunc(report.Actions, func(a, b SyncAction) int { return strings.Compare(a.Key, b.Key) })
+
+	if !o.DryRun {
+		report.Failed = append(report.Failed, c.runSync(ctx, report.Actions, o, opts)...)
+	}
+	slices.SortFunc(report.Failed, func(a, b *KeyError) int { return strings.Compare(a.Key, b.Key) })
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	if len(report.Failed) > 0 {
+		errs := make([]error, len(report.Failed))
+		for i, ke := range report.Failed {
+			errs[i] = ke
+		}
+		return report, fmt.Errorf("%d files failed: %w", len(report.Failed), errors.Join(errs...))
+	}
+
+	return report, nil
+}
+
+// runSync carries out the planned actions and returns the failures. Transfers and
+// local deletes run concurrently; deletes of objects are batched.
+func (c *Client) runSync(ctx context.Context, actions []SyncAction, o ClientOptions, opts []ClientOption) []*KeyError {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed []*KeyError
+		keys   []string
+	)
+	sem := make(chan struct{}, max(o.Concurrency, 1))
+
+	for _, action := range actions {
+		if action.Op == SyncDelete && action.Path == "" {
+			keys = append(keys, action.Key)
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			var err error
+			switch action.Op {
+			case SyncUpload:
+				err = c.syncUploadFile(ctx, action, opts)
+			case SyncDownload:
+				err = c.syncDownloadFile(ctx, action, opts)
+			case SyncDelete:
+				err = os.Remove(action.Path)
+			}
+			if err != nil {
+				mu.Lock()
+				failed = append(failed, &KeyError{Key: action.Key, Err: err})
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if len(keys) > 0 && ctx.Err() == nil {
+		seq := func(yield func(types.ObjectIdentifier, error) bool) {
+			for _, key := range keys {
+				if !yield(types.ObjectIdentifier{Key: aws.String(key)}, nil) {
+					return
+				}
+			}
+		}
+		report, _, _ := c.deleteObjects(ctx, seq, o, nil)
+		failed = append(failed, report.Failed...)
+	}
+
+	return failed
+}
+
+// syncUploadFile uploads the local file of action.
+func (c *Client) syncUploadFile(ctx context.Context, action SyncAction, opts []ClientOption) error {
+	f, err := os.Open(action.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Put(ctx, &Object{
+		Key:         action.Key,
+		ContentType: mime.TypeByExtension(path.Ext(action.Key)),
+		Size:        fi.Size(),
+		Body:        f,
+	}, opts...)
+	return err
+}
+
+// syncDownloadFile downloads the object of action into a temporary file next to the
+// destination, then moves it into place so readers never see a partial file.
+func (c *Client) syncDownloadFile(ctx context.Context, action SyncAction, opts []ClientOption) error {
+	dir := filepath.Dir(action.Path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tigris-sync-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	obj, err := c.Download(ctx, action.Key, tmp, opts...)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if !obj.LastModified.IsZero() {
+		if err := os.Chtimes(tmp.Name(), obj.LastModified, obj.LastModified); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tmp.Name(), action.Path)
+}
+
+// localFiles walks dir and returns the regular files in it that take part in the
+// sync, by slash-separated relative path. A missing dir has no files.
+func localFiles(dir string, o ClientOptions) (map[string]syncFile, error) {
+	files := map[string]syncFile{}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(path.Base(rel), ".tigris-sync-") || !syncIncluded(rel, o) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[rel] = syncFile{size: fi.Size(), modTime: fi.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// syncReason returns why a file needs to be transferred in the direction given by
+// upload, or "" if the destination exists and is up to date.
+func syncReason(local, remote syncFile, exists, upload bool, localPath string, o ClientOptions) (string, error) {
+	if !exists {
+		return "missing", nil
+	}
+	if local.size != remote.size {
+		return "size differs", nil
+	}
+
+	if o.Checksum {
+		same, ok, err := etagMatchesFile(localPath, local.size, remote.etag, o.PartSize)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			if same {
+				return "", nil
+			}
+			return "content differs", nil
+		}
+		// The ETag is not an MD5 of the content, so fall back to times.
+	}
+
+	if upload && local.modTime.After(remote.modTime) {
+		return "newer locally", nil
+	}
+	// Downloaded files get the time from the object's headers, which only has whole
+	// seconds, so ignore the fraction from listings.
+	if !upload && remote.modTime.Truncate(time.Second).After(local.modTime) {
+		return "newer in bucket", nil
+	}
+
+	return "", nil
+}
+
+// etagMatchesFile reports whether etag is the ETag S3 would give the file at p when
+// uploaded in one piece or in parts. ok is false if etag is not MD5-based, so the
+// file can't be checked against it.
+//
+// A multipart ETag is the MD5 of the parts' MD5s followed by the part count. The part
+// size is not recorded, so the sizes used by this package and by common tools that
+// give the same part count are tried.
+func etagMatchesFile(p string, size int64, etag string, partSize int64) (same, ok bool, err error) {
+	sum, count, multipart := strings.Cut(strings.Trim(etag, `"`), "-")
+	if len(sum) != 32 {
+		return false, false, nil
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return false, false, nil
+	}
+
+	if !multipart {
+		got, err := fileMD5(p)
+		if err != nil {
+			return false, true, err
+		}
+		return got == sum, true, nil
+	}
+
+	parts, err := strconv.ParseInt(count, 10, 64)
+	if err != nil || parts < 1 {
+		return false, false, nil
+	}
+
+	const mib = 1 << 20
+	candidates := []int64{
+		max(partSize, MinPartSize, (size+MaxParts-1)/MaxParts),
+		DefaultPartSize,
+		(size + parts - 1) / parts,
+		((size+parts-1)/parts + mib - 1) / mib * mib,
+	}
+
+	tried := map[int64]bool{}
+	for _, ps := range candidates {
+		if ps <= 0 || tried[ps] || (size+ps-1)/ps != parts {
+			continue
+		}
+		tried[ps] = true
+
+		sizes := make([]int64, 0, parts)
+		for left := size; left > 0; left -= ps {
+			sizes = append(sizes, min(ps, left))
+		}
+
+		got, err := filePartsMD5(p, sizes)
+		if err != nil {
+			return false, true, err
+		}
+		if got == sum {
+			return true, true, nil
+		}
+	}
+
+	return false, true, nil
+}
+
+// fileMD5 returns the hex MD5 of the file at p, as in a single-part ETag.
+func fileMD5(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// filePartsMD5 returns the hex MD5 of the MD5s of consecutive chunks of the file at p
+// with the given sizes, as in a multipart ETag. This holds for a single part too.
+func filePartsMD5(p string, sizes []int64) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	outer := md5.New()
+	for _, n := range sizes {
+		h := md5.New()
+		if _, err := io.CopyN(h, f, n); err != nil {
+			return "", err
+		}
+		outer.Write(h.Sum(nil))
+	}
+	return hex.EncodeToString(outer.Sum(nil)), nil
+}
+
+// syncIncluded reports whether the file or object at the slash-separated relative
+// path rel takes part in a sync, given the WithInclude and WithExclude patterns.
+func syncIncluded(rel string, o ClientOptions) bool {
+	if len(o.Include) > 0 && !slices.ContainsFunc(o.Include, func(pattern string) bool { return matchGlob(pattern, rel) }) {
+		return false
+	}
+	return !slices.ContainsFunc(o.Exclude, func(pattern string) bool { return matchGlob(pattern, rel) })
+}
+
+// matchGlob reports whether the slash-separated path name matches pattern. Patterns
+// use path.Match syntax, plus "**" as a whole element to match any number of
+// directories. A pattern without a slash matches the last element of name, so "*.tmp"
+// matches temporary files at any depth.
+func matchGlob(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchElems matches path elements against pattern elements.
+func matchElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchElems(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
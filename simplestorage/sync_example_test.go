@@ -0,0 +1,58 @@
+package simplestorage_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_SyncUpload() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := []simplestorage.ClientOption{
+		simplestorage.WithMirror(), // delete objects for files that no longer exist
+		simplestorage.WithExclude("*.map", ".git/**"),
+		simplestorage.WithConcurrency(16),
+	}
+
+	// Print the plan first
+	plan, err := client.SyncUpload(ctx, "./public", "www", append(opts, simplestorage.WithDryRun())...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, action := range plan.Actions {
+		fmt.Println(action)
+	}
+
+	report, err := client.SyncUpload(ctx, "./public", "www", opts...)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+	fmt.Printf("%d changed, %d unchanged\n", len(report.Actions), report.Unchanged)
+}
+
+func ExampleClient_SyncDownload() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Only fetch what changed, comparing contents instead of timestamps
+	_, err = client.SyncDownload(ctx, "models/v3", "/var/cache/models", simplestorage.WithChecksum())
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+}
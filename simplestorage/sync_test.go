@@ -0,0 +1,288 @@
+package simplestorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.tmp", "a.tmp", true},
+		{"*.tmp", "dir/sub/a.tmp", true},
+		{"*.tmp", "a.tmpx", false},
+		{"dir/*.txt", "dir/a.txt", true},
+		{"dir/*.txt", "dir/sub/a.txt", false},
+		{"dir/**", "dir/sub/a.txt", true},
+		{"dir/**", "dir", true},
+		{"dir/**", "other/a.txt", false},
+		{"**/node_modules/**", "web/node_modules/x/y.js", true},
+		{"**/node_modules/**", "node_modules/y.js", true},
+		{"**/*.go", "a/b/c.go", true},
+		{"**/*.go", "c.go", true},
+		{"a/**/z", "a/z", true},
+		{"a/**/z", "a/b/c/z", true},
+		{"a/**/z", "a/b/c/y", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEtagMatchesFile(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), int(2*MinPartSize+MinPartSize/2)/16)
+	p := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	single, err := client.Put(ctx, &Object{Key: "single", Size: int64(len(data)), Body: seekableBody(string(data))}, WithMultipartThreshold(int64(len(data))+1))
+	if err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	multi, err := client.Put(ctx, &Object{Key: "multi", Size: int64(len(data)), Body: seekableBody(string(data))}, WithMultipartThreshold(1), WithPartSize(MinPartSize))
+	if err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	// The ETag of a multipart upload with a single part, such as an 8 MiB file uploaded
+	// by the AWS CLI, is the MD5 of the part's MD5 followed by "-1".
+	partSum := md5.Sum(data)
+	onePartSum := md5.Sum(partSum[:])
+	onePart := `"` + hex.EncodeToString(onePartSum[:]) + `-1"`
+
+	tests := []struct {
+		name     string
+		etag     string
+		partSize int64
+		wantSame bool
+		wantOK   bool
+	}{
+		{name: "single part", etag: single.Etag, wantSame: true, wantOK: true},
+		{name: "multipart with known part size", etag: multi.Etag, partSize: MinPartSize, wantSame: true, wantOK: true},
+		{name: "multipart with default part size", etag: multi.Etag, partSize: DefaultPartSize, wantSame: true, wantOK: true},
+		{name: "one-part multipart", etag: onePart, wantSame: true, wantOK: true},
+		{name: "different content", etag: `"0123456789abcdef0123456789abcdef"`, wantOK: true},
+		{name: "wrong part count", etag: `"0123456789abcdef0123456789abcdef-7"`, wantOK: true},
+		{name: "not an MD5", etag: `"opaque-etag"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			same, ok, err := etagMatchesFile(p, int64(len(data)), tt.etag, tt.partSize)
+			if err != nil {
+				t.Fatalf("etagMatchesFile() failed: %v", err)
+			}
+			if same != tt.wantSame || ok != tt.wantOK {
+				t.Errorf("etagMatchesFile(%s) = %v, %v, want %v, %v", tt.etag, same, ok, tt.wantSame, tt.wantOK)
+			}
+		})
+	}
+}
+
+// writeFiles creates files under dir from a map of slash-separated paths to contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func actionStrings(actions []SyncAction) []string {
+	var out []string
+	for _, a := range actions {
+		out = append(out, string(a.Op)+" "+a.Key+" ("+a.Reason+")")
+	}
+	return out
+}
+
+func TestClient_SyncUpload(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	dir := t.TempDir()
+
+	// Backdate the files so they are older than the objects they are uploaded to
+	past := time.Now().Add(-time.Hour)
+	writeFiles(t, dir, map[string]string{
+		"index.html":     "<h1>hi</h1>",
+		"css/site.css":   "body {}",
+		"notes.tmp":      "scratch",
+		"img/logo.png":   "png",
+		"img/.DS_Store":  "junk",
+		"img/raw/a.tiff": "tiff",
+	})
+	for _, name := range []string{"index.html", "css/site.css", "img/logo.png", "img/raw/a.tiff"} {
+		os.Chtimes(filepath.Join(dir, filepath.FromSlash(name)), past, past)
+	}
+
+	opts := []ClientOption{WithExclude("*.tmp", ".DS_Store", "img/raw/**")}
+
+	plan, err := client.SyncUpload(ctx, dir, "site", append(opts, WithDryRun())...)
+	if err != nil {
+		t.Fatalf("SyncUpload() dry run failed: %v", err)
+	}
+	want := []string{
+		"upload site/css/site.css (missing)",
+		"upload site/img/logo.png (missing)",
+		"upload site/index.html (missing)",
+	}
+	if got := actionStrings(plan.Actions); !slices.Equal(got, want) {
+		t.Errorf("dry run plan = %q, want %q", got, want)
+	}
+	if got := listKeys(t, client); len(got) != 0 {
+		t.Fatalf("dry run uploaded %q", got)
+	}
+
+	if _, err := client.SyncUpload(ctx, dir, "site", opts...); err != nil {
+		t.Fatalf("SyncUpload() failed: %v", err)
+	}
+	if got, want := listKeys(t, client), []string{"site/css/site.css", "site/img/logo.png", "site/index.html"}; !slices.Equal(got, want) {
+		t.Errorf("keys after SyncUpload() = %q, want %q", got, want)
+	}
+	if obj, err := client.Head(ctx, "site/index.html"); err != nil || obj.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("Head(site/index.html) = %+v, %v, want content type text/html", obj, err)
+	}
+
+	again, err := client.SyncUpload(ctx, dir, "site", opts...)
+	if err != nil {
+		t.Fatalf("second SyncUpload() failed: %v", err)
+	}
+	if len(again.Actions) != 0 || again.Unchanged != 3 {
+		t.Errorf("second SyncUpload() = %q with %d unchanged, want nothing to do and 3 unchanged", actionStrings(again.Actions), again.Unchanged)
+	}
+
+	// Same size and content but newer: only a checksum comparison knows it's unchanged
+	now := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(dir, "index.html"), now, now)
+	writeFiles(t, dir, map[string]string{"css/site.css": "body { color: red }"})
+	putTestObject(t, client, "site/stale.html", []byte("old"))
+
+	plan, err = client.SyncUpload(ctx, dir, "site", append(opts, WithMirror(), WithDryRun())...)
+	if err != nil {
+		t.Fatalf("SyncUpload() dry run failed: %v", err)
+	}
+	want = []string{
+		"upload site/css/site.css (size differs)",
+		"upload site/index.html (newer locally)",
+		"delete site/stale.html (not in source)",
+	}
+	if got := actionStrings(plan.Actions); !slices.Equal(got, want) {
+		t.Errorf("mirror plan = %q, want %q", got, want)
+	}
+
+	report, err := client.SyncUpload(ctx, dir, "site", append(opts, WithMirror(), WithChecksum())...)
+	if err != nil {
+		t.Fatalf("SyncUpload() with mirror failed: %v", err)
+	}
+	want = []string{
+		"upload site/css/site.css (size differs)",
+		"delete site/stale.html (not in source)",
+	}
+	if got := actionStrings(report.Actions); !slices.Equal(got, want) {
+		t.Errorf("mirror with checksum = %q, want %q", got, want)
+	}
+	if got, want := listKeys(t, client), []string{"site/css/site.css", "site/img/logo.png", "site/index.html"}; !slices.Equal(got, want) {
+		t.Errorf("keys after mirror = %q, want %q", got, want)
+	}
+	if got := readTestObject(t, client, "site/css/site.css"); got != "body { color: red }" {
+		t.Errorf("site/css/site.css = %q after sync", got)
+	}
+}
+
+func TestClient_SyncDownload(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	dir := t.TempDir()
+
+	putTestObject(t, client, "backup/db.sql", []byte("create table"))
+	putTestObject(t, client, "backup/logs/1.log", []byte("one"))
+	putTestObject(t, client, "backup/logs/2.log", []byte("two"))
+	putTestObject(t, client, "other/x", []byte("not synced"))
+	writeFiles(t, dir, map[string]string{"extra.txt": "local only", "keep.lock": "excluded"})
+
+	opts := []ClientOption{WithExclude("*.lock"), WithMirror()}
+
+	report, err := client.SyncDownload(ctx, "backup/", dir, opts...)
+	if err != nil {
+		t.Fatalf("SyncDownload() failed: %v", err)
+	}
+	want := []string{
+		"download backup/db.sql (missing)",
+		"delete backup/extra.txt (not in source)",
+		"download backup/logs/1.log (missing)",
+		"download backup/logs/2.log (missing)",
+	}
+	if got := actionStrings(report.Actions); !slices.Equal(got, want) {
+		t.Errorf("SyncDownload() = %q, want %q", got, want)
+	}
+
+	for name, data := range map[string]string{"db.sql": "create table", "logs/2.log": "two", "keep.lock": "excluded"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != data {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "extra.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("extra.txt still exists after mirroring: %v", err)
+	}
+
+	again, err := client.SyncDownload(ctx, "backup/", dir, opts...)
+	if err != nil {
+		t.Fatalf("second SyncDownload() failed: %v", err)
+	}
+	if len(again.Actions) != 0 || again.Unchanged != 3 {
+		t.Errorf("second SyncDownload() = %q with %d unchanged, want nothing to do and 3 unchanged", actionStrings(again.Actions), again.Unchanged)
+	}
+
+	putTestObject(t, client, "backup/logs/1.log", []byte("one, updated"))
+	report, err = client.SyncDownload(ctx, "backup/", dir, opts...)
+	if err != nil {
+		t.Fatalf("third SyncDownload() failed: %v", err)
+	}
+	if got, want := actionStrings(report.Actions), []string{"download backup/logs/1.log (size differs)"}; !slices.Equal(got, want) {
+		t.Errorf("third SyncDownload() = %q, want %q", got, want)
+	}
+}
+
+func TestClient_SyncDownload_escapingKey(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	dir := filepath.Join(t.TempDir(), "out")
+
+	putTestObject(t, client, "pub/ok.txt", []byte("fine"))
+	putTestObject(t, client, "pub/../../evil.txt", []byte("nope"))
+
+	report, err := client.SyncDownload(ctx, "pub", dir)
+	if err == nil {
+		t.Fatal("SyncDownload() of an escaping key succeeded, want error")
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Key != "pub/../../evil.txt" {
+		t.Errorf("Failed = %v, want the escaping key", report.Failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ok.txt")); err != nil {
+		t.Errorf("ok.txt was not downloaded: %v", err)
+	}
+}
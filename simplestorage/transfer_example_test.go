@@ -0,0 +1,102 @@
+package simplestorage_test
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	simplestorage "github.com/tigrisdata/storage-go/simplestorage"
+)
+
+func ExampleClient_Put_multipart() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fin, err := os.Open("backup.tar")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer fin.Close()
+
+	// Large bodies and bodies of unknown size are uploaded in parallel parts
+	obj, err := client.Put(ctx, &simplestorage.Object{
+		Key:  "backups/backup.tar",
+		Body: fin,
+	},
+		simplestorage.WithPartSize(16<<20),
+		simplestorage.WithConcurrency(8),
+	)
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	fmt.Println("Uploaded", obj.Size, "bytes with ETag", obj.Etag)
+}
+
+func ExampleClient_Download() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fout, err := os.Create("model.safetensors")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer fout.Close()
+
+	// Fetch 32 MiB ranges over 16 connections at once
+	obj, err := client.Download(ctx, "models/model.safetensors", fout,
+		simplestorage.WithPartSize(32<<20),
+		simplestorage.WithConcurrency(16),
+	)
+	if errors.Is(err, simplestorage.ErrObjectChanged) {
+		log.Fatal("object was overwritten during the download, try again")
+	}
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	fmt.Println("Downloaded", obj.Size, "bytes")
+}
+
+func ExampleClient_Open() {
+	ctx := context.Background()
+
+	client, err := simplestorage.New(ctx,
+		simplestorage.WithBucket("my-default-bucket"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep up to 16 blocks of 64 KiB in memory while reading
+	r, err := client.Open(ctx, "archives/logs.zip", simplestorage.WithBlockCache(64<<10, 16))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+
+	// Formats that need random access can read straight from Tigris
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		log.Fatal(err) // handle the error here
+	}
+
+	for _, f := range zr.File {
+		fmt.Println(f.Name)
+	}
+}
@@ -0,0 +1,118 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+// DefaultMaxAttempts is how many times Update tries its read-modify-write cycle
+// unless overridden with WithMaxAttempts.
+const DefaultMaxAttempts = 10
+
+// Backoff between Update attempts grows from minUpdateBackoff up to maxUpdateBackoff,
+// with jitter so competing writers don't retry in lockstep.
+const (
+	minUpdateBackoff = 20 * time.Millisecond
+	maxUpdateBackoff = time.Second
+)
+
+// Update atomically replaces the object at key with the result of fn, retrying if
+// another writer changes the object in between.
+//
+// Each attempt reads the object with a consistent (compare-and-swap) read and passes
+// it to fn, or passes nil if there is no object at key. The object fn returns is then
+// written only if the object is still unchanged: with If-Match on the ETag that was
+// read, or only if it is still absent. If that precondition fails, Update waits with
+// jittered exponential backoff and tries again, up to the number of attempts set with
+// WithMaxAttempts. Once they are used up it returns a *ConditionError matching
+// ErrPreconditionFailed.
+//
+// fn may be called several times, so it must not have side effects. The old object's
+// Body is closed when fn returns. The returned object's Key is set to key. If fn
+// returns a nil Object, nothing is written and Update returns nil. If fn returns an
+// error, Update stops and returns it.
+//
+// The object is written with Put, so large objects and bodies of unknown size are
+// uploaded in parts, and the precondition is checked when the upload is completed.
+func (c *Client) Update(ctx context.Context, key string, fn func(old *Object) (*Object, error), opts ...ClientOption) (*Object, error) {
+	o := new(ClientOptions).defaults(c.options)
+
+	for _, doer := range opts {
+		doer(&o)
+	}
+
+	attempts := max(o.MaxAttempts, 1)
+	casOpts := append(slices.Clip(opts), WithS3Options(tigrisheaders.WithCompareAndSwap()))
+
+	var lastErr error
+	for attempt := range attempts {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, attempt); err != nil {
+				return nil, fmt.Errorf("simplestorage: can't update %s/%s: %w", o.BucketName, key, err)
+			}
+		}
+
+		old, err := c.Get(ctx, key, casOpts...)
+		if err != nil && !errors.Is(err, ErrObjectNotFound) {
+			return nil, fmt.Errorf("simplestorage: can't update %s/%s: %w", o.BucketName, key, err)
+		}
+
+		cond := tigrisheaders.WithCreateObjectIfNotExists()
+		if old != nil {
+			cond = tigrisheaders.WithIfEtagMatches(old.Etag)
+		}
+
+		obj, err := callUpdate(fn, old)
+		if err != nil {
+			return nil, fmt.Errorf("simplestorage: can't update %s/%s: %w", o.BucketName, key, err)
+		}
+		if obj == nil {
+			return nil, nil
+		}
+		obj.Key = key
+
+		result, err := c.Put(ctx, obj, append(slices.Clip(opts), WithS3Options(cond))...)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, ErrPreconditionFailed) && !errors.Is(err, ErrObjectNotFound) {
+			return nil, fmt.Errorf("simplestorage: can't update %s/%s: %w", o.BucketName, key, err)
+		}
+
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("simplestorage: can't update %s/%s: gave up after %d attempts: %w", o.BucketName, key, attempts, c.conditionFailed(ctx, key, lastErr, opts))
+}
+
+// callUpdate calls fn with old and closes old's Body afterwards.
+func callUpdate(fn func(old *Object) (*Object, error), old *Object) (*Object, error) {
+	if old != nil && old.Body != nil {
+		defer old.Body.Close()
+	}
+
+	return fn(old)
+}
+
+// sleepBackoff waits before the given retry attempt (counting from 1), returning
+// early with ctx's error if ctx is done.
+func sleepBackoff(ctx context.Context, attempt int) error {
+	d := min(minUpdateBackoff<<min(attempt-1, 16), maxUpdateBackoff)
+	d = d/2 + rand.N(d/2+1)
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+package simplestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// incrementCounter is an Update callback that treats the object as a decimal counter
+// and adds one, starting from zero if there is no object.
+func incrementCounter(old *Object) (*Object, error) {
+	n := 0
+	if old != nil {
+		data, err := io.ReadAll(old.Body)
+		if err != nil {
+			return nil, err
+		}
+		if n, err = strconv.Atoi(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	next := strconv.Itoa(n + 1)
+	return &Object{Size: int64(len(next)), Body: seekableBody(next)}, nil
+}
+
+func TestClient_Update(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	var sawMissing bool
+	first, err := client.Update(ctx, "counter", func(old *Object) (*Object, error) {
+		sawMissing = old == nil
+		return incrementCounter(old)
+	})
+	if err != nil {
+		t.Fatalf("Update() of a missing key failed: %v", err)
+	}
+	if !sawMissing {
+		t.Error("Update() of a missing key passed a non-nil old object")
+	}
+	if first.Key != "counter" || first.Etag == "" {
+		t.Errorf("Update() = key %q ETag %q, want key counter and an ETag", first.Key, first.Etag)
+	}
+
+	second, err := client.Update(ctx, "counter", incrementCounter)
+	if err != nil {
+		t.Fatalf("Update() of an existing key failed: %v", err)
+	}
+	if second.Etag == first.Etag {
+		t.Error("Update() did not change the ETag")
+	}
+	if got := readTestObject(t, client, "counter"); got != "2" {
+		t.Errorf("counter = %s, want 2", got)
+	}
+
+	t.Run("nil object writes nothing", func(t *testing.T) {
+		obj, err := client.Update(ctx, "counter", func(*Object) (*Object, error) { return nil, nil })
+		if err != nil || obj != nil {
+			t.Fatalf("Update() = %v, %v, want nil, nil", obj, err)
+		}
+		if got := readTestObject(t, client, "counter"); got != "2" {
+			t.Errorf("counter = %s, want 2", got)
+		}
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		_, err := client.Update(ctx, "counter", func(*Object) (*Object, error) { return nil, errBoom })
+		if !errors.Is(err, errBoom) {
+			t.Errorf("Update() error = %v, want %v", err, errBoom)
+		}
+	})
+}
+
+func TestClient_Update_concurrent(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	for _, key := range []string{"new-counter", "existing-counter"} {
+		t.Run(key, func(t *testing.T) {
+			if key == "existing-counter" {
+				putTestObject(t, client, key, []byte("0"))
+			}
+
+			const writers = 8
+
+			var wg sync.WaitGroup
+			errs := make(chan error, writers)
+			for range writers {
+				wg.Go(func() {
+					if _, err := client.Update(ctx, key, incrementCounter, WithMaxAttempts(100)); err != nil {
+						errs <- err
+					}
+				})
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Errorf("Update() failed: %v", err)
+			}
+			if got := readTestObject(t, client, key); got != strconv.Itoa(writers) {
+				t.Errorf("counter = %s, want %d", got, writers)
+			}
+		})
+	}
+}
+
+func TestClient_Update_multipart(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	// grow replaces the object with one part's worth of bytes more than it had, as a
+	// body of unknown size.
+	grow := func(old *Object) (*Object, error) {
+		size := MinPartSize + 1
+		if old != nil {
+			size += old.Size
+		}
+		return &Object{Body: io.NopCloser(io.LimitReader(zeroReader{}, size))}, nil
+	}
+
+	for i, want := range []int64{MinPartSize + 1, 2*MinPartSize + 2} {
+		obj, err := client.Update(ctx, "blob", grow, WithPartSize(MinPartSize))
+		if err != nil {
+			t.Fatalf("Update() #%d failed: %v", i+1, err)
+		}
+		if obj.Size != want {
+			t.Errorf("Update() #%d size = %d, want %d", i+1, obj.Size, want)
+		}
+	}
+}
+
+func TestClient_Update_givesUp(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	putTestObject(t, client, "contended", []byte("0"))
+
+	calls := 0
+	_, err := client.Update(ctx, "contended", func(old *Object) (*Object, error) {
+		calls++
+		// Another writer sneaks in between every read and write
+		putTestObject(t, client, "contended", []byte(strconv.Itoa(100+calls)))
+		return incrementCounter(old)
+	}, WithMaxAttempts(3))
+
+	var ce *ConditionError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Update() error = %v, want *ConditionError matching ErrPreconditionFailed", err)
+	}
+	if calls != 3 {
+		t.Errorf("callback called %d times, want 3", calls)
+	}
+	if got := readTestObject(t, client, "contended"); got != "103" {
+		t.Errorf("object = %s, want the other writer's 103", got)
+	}
+}
@@ -0,0 +1,264 @@
+package simplestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const (
+	// DefaultMultipartThreshold is the object size at which Put switches to a
+	// multipart upload unless overridden with WithMultipartThreshold.
+	DefaultMultipartThreshold int64 = 64 << 20
+
+	// DefaultPartSize is the size of each part in multipart transfers unless
+	// overridden with WithPartSize.
+	DefaultPartSize int64 = 8 << 20
+
+	// DefaultConcurrency is the number of parts transferred in parallel unless
+	// overridden with WithConcurrency.
+	DefaultConcurrency = 4
+
+	// MinPartSize is the smallest part size S3 accepts for all but the last part
+	// of a multipart upload.
+	MinPartSize int64 = 5 << 20
+
+	// MaxParts is the largest number of parts a multipart upload can have.
+	MaxParts = 10000
+)
+
+// putMultipart uploads obj, switching to a multipart upload for large objects and
+// for bodies of unknown size that don't fit in a single part.
+func (c *Client) putMultipart(ctx context.Context, obj *Object, o ClientOptions) (*Object, error) {
+	partSize := max(o.PartSize, MinPartSize)
+	if obj.Size > 0 {
+		partSize = max(partSize, (obj.Size+MaxParts-1)/MaxParts)
+	}
+
+	body := io.Reader(obj.Body)
+	if body == nil {
+		body = bytes.NewReader(nil)
+	}
+
+	first, err := readPart(body, partSize)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("simplestorage: can't put %s/%s: reading body: %w", o.BucketName, obj.Key, err)
+	}
+
+	// Streams of unknown size that end within the first part are sent as a single
+	// PutObject call from memory.
+	if obj.Size <= 0 && int64(len(first)) < partSize && int64(len(first)) < o.MultipartThreshold {
+		resp, err := c.cli.PutObject(
+			ctx,
+			&s3.PutObjectInput{
+				Bucket:        aws.String(o.BucketName),
+				Key:           aws.String(obj.Key),
+				Body:          bytes.NewReader(first),
+				ContentType:   raise(obj.ContentType),
+				ContentLength: aws.Int64(int64(len(first))),
+			},
+			o.S3Options...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("simplestorage: can't put %s/%s: %w", o.BucketName, obj.Key, err)
+		}
+
+		obj.Bucket = o.BucketName
+		obj.Size = int64(len(first))
+		obj.Etag = lower(resp.ETag, "")
+		obj.Version = lower(resp.VersionId, "")
+
+		return obj, nil
+	}
+
+	create, err := c.cli.CreateMultipartUpload(
+		ctx,
+		&s3.CreateMultipartUploadInput{
+			Bucket:      aws.String(o.BucketName),
+			Key:         aws.String(obj.Key),
+			ContentType: raise(obj.ContentType),
+		},
+		partOptions(o)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("simplestorage: can't put %s/%s: starting multipart upload: %w", o.BucketName, obj.Key, err)
+	}
+	uploadID := create.UploadId
+
+	parts, total, err := c.uploadParts(ctx, body, first, partSize, uploadID, obj.Key, o)
+	if err == nil && obj.Size > 0 && total != obj.Size {
+		err = fmt.Errorf("body has %d bytes, want %d", total, obj.Size)
+	}
+
+	if err != nil {
+		// Abort even if ctx was cancelled so the parts don't linger and get billed.
+		if _, abortErr := c.cli.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(o.BucketName),
+			Key:      aws.String(obj.Key),
+			UploadId: uploadID,
+		}, partOptions(o)...); abortErr != nil {
+			err = errors.Join(err, fmt.Errorf("aborting multipart upload: %w", abortErr))
+		}
+		return nil, fmt.Errorf("simplestorage: can't put %s/%s: %w", o.BucketName, obj.Key, err)
+	}
+
+	resp, err := c.cli.CompleteMultipartUpload(
+		ctx,
+		&s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(o.BucketName),
+			Key:             aws.String(obj.Key),
+			UploadId:        uploadID,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		},
+		o.S3Options...,
+	)
+	if err != nil {
+		err = fmt.Errorf("completing multipart upload: %w", err)
+		if _, abortErr := c.cli.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(o.BucketName),
+			Key:      aws.String(obj.Key),
+			UploadId: uploadID,
+		}, partOptions(o)...); abortErr != nil {
+			err = errors.Join(err, fmt.Errorf("aborting multipart upload: %w", abortErr))
+		}
+		return nil, fmt.Errorf("simplestorage: can't put %s/%s: %w", o.BucketName, obj.Key, err)
+	}
+
+	obj.Bucket = o.BucketName
+	obj.Size = total
+	obj.Etag = lower(resp.ETag, "")
+	obj.Version = lower(resp.VersionId, "")
+
+	return obj, nil
+}
+
+// uploadParts reads body in partSize chunks, starting with the already-read first
+// chunk, and uploads them in parallel. It returns the completed parts in order and
+// the total number of bytes uploaded.
+func (c *Client) uploadParts(ctx context.Context, body io.Reader, first []byte, partSize int64, uploadID *string, key string, o ClientOptions) ([]types.CompletedPart, int64, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		parts []types.CompletedPart
+		total int64
+	)
+
+	sem := make(chan struct{}, max(o.Concurrency, 1))
+	data := first
+
+	for partNumber := int32(1); ; partNumber++ {
+		if partNumber > 1 {
+			var err error
+			data, err = readPart(body, partSize)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				cancel(fmt.Errorf("reading body: %w", err))
+				break
+			}
+		}
+
+		if partNumber > MaxParts {
+			cancel(fmt.Errorf("body needs more than %d parts of %d bytes", MaxParts, partSize))
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		total += int64(len(data))
+		wg.Add(1)
+		go func(partNumber int32, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := c.cli.UploadPart(ctx, &s3.UploadPartInput{
+				Bucket:        aws.String(o.BucketName),
+				Key:           aws.String(key),
+				UploadId:      uploadID,
+				PartNumber:    aws.Int32(partNumber),
+				Body:          bytes.NewReader(data),
+				ContentLength: aws.Int64(int64(len(data))),
+			}, partOptions(o)...)
+			if err != nil {
+				cancel(fmt.Errorf("uploading part %d: %w", partNumber, err))
+				return
+			}
+
+			mu.Lock()
+			parts = append(parts, types.CompletedPart{
+				ETag:       resp.ETag,
+				PartNumber: aws.Int32(partNumber),
+			})
+			mu.Unlock()
+		}(partNumber, data)
+
+		if int64(len(data)) < partSize {
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	slices.SortFunc(parts, func(a, b types.CompletedPart) int {
+		return int(aws.ToInt32(a.PartNumber) - aws.ToInt32(b.PartNumber))
+	})
+
+	return parts, total, nil
+}
+
+// partOptions returns the S3 options for the calls of a multipart upload other than
+// CompleteMultipartUpload. If-Match and If-None-Match are only checked when the upload
+// is completed, and are rejected on the other calls, so they are left out.
+func partOptions(o ClientOptions) []func(*s3.Options) {
+	return append(slices.Clip(o.S3Options), withoutWriteConditions)
+}
+
+// withoutWriteConditions removes the If-Match and If-None-Match headers from a request.
+func withoutWriteConditions(o *s3.Options) {
+	o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("withoutWriteConditions", func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			if req, ok := in.Request.(*smithyhttp.Request); ok {
+				req.Header.Del("If-Match")
+				req.Header.Del("If-None-Match")
+			}
+			return next.HandleFinalize(ctx, in)
+		}), middleware.Before)
+	})
+}
+
+// readPart reads up to size bytes from r. It returns io.EOF only if no bytes were read.
+func readPart(r io.Reader, size int64) ([]byte, error) {
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, size)
+	switch {
+	case n == 0 && err == io.EOF:
+		return nil, io.EOF
+	case err != nil && err != io.EOF:
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
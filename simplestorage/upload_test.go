@@ -0,0 +1,177 @@
+package simplestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+)
+
+// failingReader returns data and then fails with err.
+type failingReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func (r *failingReader) Close() error { return nil }
+
+func TestPut_multipart(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789abcdef"), (12<<20)/16+7)
+
+	tests := []struct {
+		name      string
+		size      int64
+		body      []byte
+		opts      []ClientOption
+		wantParts string
+	}{
+		{
+			name:      "known size above threshold",
+			size:      int64(len(payload)),
+			body:      payload,
+			opts:      []ClientOption{WithMultipartThreshold(MinPartSize), WithPartSize(MinPartSize), WithConcurrency(2)},
+			wantParts: "-3\"",
+		},
+		{
+			name:      "unknown size larger than a part",
+			body:      payload,
+			opts:      []ClientOption{WithPartSize(MinPartSize)},
+			wantParts: "-3\"",
+		},
+		{
+			name: "unknown size smaller than a part",
+			body: []byte("small stream"),
+		},
+		{
+			name: "empty body of unknown size",
+			body: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newTestClient(t)
+
+			obj, err := client.Put(ctx, &Object{
+				Key:  "object",
+				Size: tt.size,
+				Body: io.NopCloser(bytes.NewReader(tt.body)),
+			}, tt.opts...)
+			if err != nil {
+				t.Fatalf("Put() failed: %v", err)
+			}
+
+			if obj.Size != int64(len(tt.body)) {
+				t.Errorf("Put() size = %d, want %d", obj.Size, len(tt.body))
+			}
+			if tt.wantParts != "" && !strings.HasSuffix(obj.Etag, tt.wantParts) {
+				t.Errorf("Put() ETag = %s, want multipart ETag ending in %s", obj.Etag, tt.wantParts)
+			}
+			if tt.wantParts == "" && strings.Contains(obj.Etag, "-") {
+				t.Errorf("Put() ETag = %s, want single-part ETag", obj.Etag)
+			}
+
+			got, err := client.Get(ctx, "object")
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			defer got.Body.Close()
+			data, _ := io.ReadAll(got.Body)
+			if !bytes.Equal(data, tt.body) {
+				t.Errorf("Get() returned %d bytes, want %d matching bytes", len(data), len(tt.body))
+			}
+			if got.Etag != obj.Etag {
+				t.Errorf("Get() ETag = %s, want %s", got.Etag, obj.Etag)
+			}
+		})
+	}
+}
+
+func TestPut_multipartAbortsOnFailure(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	errBoom := errors.New("boom")
+	_, err := client.Put(ctx, &Object{
+		Key: "broken",
+		Body: &failingReader{
+			data: bytes.NewReader(make([]byte, MinPartSize+1)),
+			err:  errBoom,
+		},
+	}, WithPartSize(MinPartSize))
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Put() error = %v, want %v", err, errBoom)
+	}
+
+	uploads, err := client.cli.ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{Bucket: aws.String("test-bucket")})
+	if err != nil {
+		t.Fatalf("ListMultipartUploads() failed: %v", err)
+	}
+	if len(uploads.Uploads) != 0 {
+		t.Errorf("found %d multipart uploads after failed Put(), want 0", len(uploads.Uploads))
+	}
+
+	if _, err := client.Head(ctx, "broken"); err == nil {
+		t.Error("Head() found an object after failed Put(), want none")
+	}
+}
+
+func TestPut_multipartSizeMismatch(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	_, err := client.Put(ctx, &Object{
+		Key:  "short",
+		Size: MinPartSize * 2,
+		Body: io.NopCloser(bytes.NewReader(make([]byte, MinPartSize))),
+	}, WithMultipartThreshold(MinPartSize))
+	if err == nil {
+		t.Fatal("Put() with a body shorter than Size succeeded, want error")
+	}
+}
+
+func TestPut_multipartConditional(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	// body is larger than one part and has no size, so it is uploaded in two parts.
+	body := func() *Object {
+		return &Object{Key: "k", Body: io.NopCloser(bytes.NewReader(make([]byte, MinPartSize+10)))}
+	}
+	createOnly := WithS3Options(tigrisheaders.WithCreateObjectIfNotExists())
+
+	obj, err := client.Put(ctx, body(), WithPartSize(MinPartSize), createOnly)
+	if err != nil {
+		t.Fatalf("Put() of a new key with If-Match failed: %v", err)
+	}
+	if obj.Size != MinPartSize+10 || !strings.HasSuffix(obj.Etag, `-2"`) {
+		t.Errorf("Put() = size %d ETag %s, want a two-part upload of %d bytes", obj.Size, obj.Etag, MinPartSize+10)
+	}
+
+	if _, err := client.Put(ctx, body(), WithPartSize(MinPartSize), createOnly); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Put() over an existing key with If-Match error = %v, want ErrPreconditionFailed", err)
+	}
+
+	uploads, err := client.cli.ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{Bucket: aws.String("test-bucket")})
+	if err != nil {
+		t.Fatalf("ListMultipartUploads() failed: %v", err)
+	}
+	if len(uploads.Uploads) != 0 {
+		t.Errorf("found %d multipart uploads after a failed condition, want 0", len(uploads.Uploads))
+	}
+}
@@ -140,6 +140,7 @@ func New(ctx context.Context, options ...Option) (*Client, error) {
 		if creds != nil {
 			opts.Credentials = creds
 		}
+		opts.APIOptions = append(opts.APIOptions, addErrorMiddleware)
 	})
 
 	return &Client{
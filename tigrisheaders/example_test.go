@@ -42,6 +42,21 @@ func ExampleWithQuery() {
 	}
 }
 
+func ExampleQuery() {
+	// Build a query without hand-quoting fields and values
+	query := tigrisheaders.Query().
+		Where(tigrisheaders.FieldContentType).Eq("image/png").
+		And(tigrisheaders.FieldSize).Gt(1024).
+		And("uploaded-by").Eq(`user "input"`)
+
+	_, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String("my-bucket"),
+	}, tigrisheaders.WithQueryBuilder(query))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func ExampleWithCreateObjectIfNotExists() {
 	// Create object only if it doesn't exist
 	_, err := client.PutObject(ctx, &s3.PutObjectInput{},
@@ -60,6 +75,15 @@ func ExampleWithIfEtagMatches() {
 	}
 }
 
+func ExampleWithIfEtagDoesNotMatch() {
+	// Only fetch the object if it changed since it was cached
+	_, err := client.GetObject(ctx, &s3.GetObjectInput{},
+		tigrisheaders.WithIfEtagDoesNotMatch(`"abc123"`))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func ExampleWithModifiedSince() {
 	// Only proceed if modified since date
 	_, err := client.GetObject(ctx, &s3.GetObjectInput{},
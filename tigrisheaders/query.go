@@ -0,0 +1,289 @@
+package tigrisheaders
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Built-in fields that can be used in metadata queries.
+//
+// Any other field name refers to a user metadata key (set with the x-amz-meta-* headers).
+const (
+	FieldKey          = "$key"          // The object key
+	FieldSize         = "$size"         // The object size in bytes
+	FieldLastModified = "$lastModified" // The time the object was last modified
+	FieldContentType  = "Content-Type"  // The MIME type of the object
+	FieldETag         = "$etag"         // The entity tag of the object
+)
+
+// builtinFields is the set of $-prefixed fields Tigris understands.
+var builtinFields = map[string]bool{
+	FieldKey:          true,
+	FieldSize:         true,
+	FieldLastModified: true,
+	FieldETag:         true,
+}
+
+// ErrInvalidQuery is wrapped by all errors returned from building an invalid query.
+var ErrInvalidQuery = errors.New("tigrisheaders: invalid query")
+
+// QueryBuilder builds a Tigris metadata query (the X-Tigris-Query header) with
+// correct quoting and escaping of fields and values.
+//
+// QueryBuilder values are immutable: every method returns a new builder, so a partial
+// query can safely be reused as the base for several others. Errors are recorded as
+// the query is built and reported by Build.
+//
+// For more information, see the Tigris documentation[1].
+//
+// [1]: https://www.tigrisdata.com/docs/objects/query-metadata/
+type QueryBuilder struct {
+	expr string
+	err  error
+}
+
+// Query starts a new, empty metadata query.
+func Query() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Condition is a comparison on a single field that is waiting for its operator and
+// value. Finish it with one of Eq, Ne, Lt, Le, Gt or Ge.
+type Condition struct {
+	q     *QueryBuilder
+	field string
+	join  string
+}
+
+// Where starts the query with a condition on field.
+func (q *QueryBuilder) Where(field string) *Condition {
+	if q.expr != "" {
+		return &Condition{q: q.withErr(fmt.Errorf("%w: Where called on a non-empty query, use And or Or", ErrInvalidQuery)), field: field}
+	}
+	return &Condition{q: q, field: field}
+}
+
+// And adds a condition on field that must hold in addition to the existing query.
+func (q *QueryBuilder) And(field string) *Condition {
+	return q.join("AND", field)
+}
+
+// Or adds a condition on field that may hold instead of the existing query.
+func (q *QueryBuilder) Or(field string) *Condition {
+	return q.join("OR", field)
+}
+
+// AndGroup adds a parenthesised sub-query that must hold in addition to the existing query.
+func (q *QueryBuilder) AndGroup(sub *QueryBuilder) *QueryBuilder {
+	return q.group("AND", sub)
+}
+
+// OrGroup adds a parenthesised sub-query that may hold instead of the existing query.
+func (q *QueryBuilder) OrGroup(sub *QueryBuilder) *QueryBuilder {
+	return q.group("OR", sub)
+}
+
+// Build validates the query and returns the header value to send as X-Tigris-Query.
+func (q *QueryBuilder) Build() (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if q.expr == "" {
+		return "", fmt.Errorf("%w: query has no conditions", ErrInvalidQuery)
+	}
+	return "WHERE " + q.expr, nil
+}
+
+// Err returns the first error recorded while building the query, if any.
+func (q *QueryBuilder) Err() error {
+	_, err := q.Build()
+	return err
+}
+
+// String returns the query as it would be sent, or an empty string if it is invalid.
+func (q *QueryBuilder) String() string {
+	s, _ := q.Build()
+	return s
+}
+
+// Eq adds a condition that field is equal to value.
+func (c *Condition) Eq(value any) *QueryBuilder { return c.compare("=", value) }
+
+// Ne adds a condition that field is not equal to value.
+func (c *Condition) Ne(value any) *QueryBuilder { return c.compare("!=", value) }
+
+// Lt adds a condition that field is less than value.
+func (c *Condition) Lt(value any) *QueryBuilder { return c.compare("<", value) }
+
+// Le adds a condition that field is less than or equal to value.
+func (c *Condition) Le(value any) *QueryBuilder { return c.compare("<=", value) }
+
+// Gt adds a condition that field is greater than value.
+func (c *Condition) Gt(value any) *QueryBuilder { return c.compare(">", value) }
+
+// Ge adds a condition that field is greater than or equal to value.
+func (c *Condition) Ge(value any) *QueryBuilder { return c.compare(">=", value) }
+
+// WithQueryBuilder sets the X-Tigris-Query header from a QueryBuilder in a ListObjectsV2 request.
+//
+// If the query is invalid, the request fails with an error wrapping ErrInvalidQuery
+// before anything is sent to Tigris.
+func WithQueryBuilder(q *QueryBuilder) func(*s3.Options) {
+	query, err := q.Build()
+	if err != nil {
+		return func(options *s3.Options) {
+			options.APIOptions = append(options.APIOptions, func(*middleware.Stack) error {
+				return err
+			})
+		}
+	}
+
+	return WithQuery(query)
+}
+
+func (q *QueryBuilder) withErr(err error) *QueryBuilder {
+	if q.err != nil {
+		return q
+	}
+	return &QueryBuilder{expr: q.expr, err: err}
+}
+
+func (q *QueryBuilder) join(op, field string) *Condition {
+	if q.expr == "" {
+		return &Condition{q: q.withErr(fmt.Errorf("%w: %s called before Where", ErrInvalidQuery, op)), field: field}
+	}
+	return &Condition{q: q, field: field, join: op}
+}
+
+func (q *QueryBuilder) group(op string, sub *QueryBuilder) *QueryBuilder {
+	switch {
+	case q.err != nil:
+		return q
+	case sub == nil || sub.expr == "":
+		return q.withErr(fmt.Errorf("%w: empty sub-query", ErrInvalidQuery))
+	case sub.err != nil:
+		return q.withErr(sub.err)
+	case q.expr == "":
+		return q.withErr(fmt.Errorf("%w: %s called before Where", ErrInvalidQuery, op))
+	}
+	return &QueryBuilder{expr: q.expr + " " + op + " (" + sub.expr + ")"}
+}
+
+func (c *Condition) compare(op string, value any) *QueryBuilder {
+	q := c.q
+	if q.err != nil {
+		return q
+	}
+
+	field, err := quoteField(c.field)
+	if err != nil {
+		return q.withErr(err)
+	}
+
+	lit, err := quoteValue(value)
+	if err != nil {
+		return q.withErr(fmt.Errorf("%w: field %s: %w", ErrInvalidQuery, field, err))
+	}
+
+	cond := field + " " + op + " " + lit
+	if c.join == "" {
+		return &QueryBuilder{expr: cond}
+	}
+
+	// Wrap the existing expression when mixing AND and OR so the query reads in the
+	// order it was built rather than by SQL operator precedence.
+	expr := q.expr
+	if c.join == "AND" && strings.Contains(expr, " OR ") {
+		expr = "(" + expr + ")"
+	}
+	return &QueryBuilder{expr: expr + " " + c.join + " " + cond}
+}
+
+// quoteField validates and quotes a field name.
+func quoteField(field string) (string, error) {
+	switch {
+	case field == "":
+		return "", fmt.Errorf("%w: empty field name", ErrInvalidQuery)
+	case strings.HasPrefix(field, "$"):
+		if !builtinFields[field] {
+			return "", fmt.Errorf("%w: unknown built-in field %q", ErrInvalidQuery, field)
+		}
+		return field, nil
+	case strings.ContainsRune(field, '`'):
+		return "", fmt.Errorf("%w: field name %q contains a backtick", ErrInvalidQuery, field)
+	case strings.IndexFunc(field, unicode.IsControl) >= 0:
+		return "", fmt.Errorf("%w: field name %q contains control characters", ErrInvalidQuery, field)
+	}
+	return "`" + field + "`", nil
+}
+
+// quoteValue formats a Go value as a query literal.
+func quoteValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return quoteString(v)
+	case time.Time:
+		return quoteString(v.UTC().Format(time.RFC3339))
+	case fmt.Stringer:
+		return quoteString(v.String())
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return quoteFloat(float64(v), 32)
+	case float64:
+		return quoteFloat(v, 64)
+	}
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
+
+// quoteFloat formats a finite floating point number as a query literal.
+func quoteFloat(f float64, bitSize int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("non-finite number %v", f)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize), nil
+}
+
+// quoteString quotes s as a double-quoted string literal, escaping quotes and backslashes.
+func quoteString(s string) (string, error) {
+	if strings.IndexFunc(s, unicode.IsControl) >= 0 {
+		return "", errors.New("string value contains control characters")
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('"')
+	return sb.String(), nil
+}
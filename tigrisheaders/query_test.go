@@ -0,0 +1,161 @@
+package tigrisheaders
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *QueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "single string condition",
+			query: Query().Where("Content-Type").Eq("image/png"),
+			want:  "WHERE `Content-Type` = \"image/png\"",
+		},
+		{
+			name:  "built-in numeric field",
+			query: Query().Where(FieldSize).Gt(1024),
+			want:  "WHERE $size > 1024",
+		},
+		{
+			name:  "time value",
+			query: Query().Where(FieldLastModified).Ge(time.Date(2023, 1, 15, 8, 30, 0, 0, time.UTC)),
+			want:  "WHERE $lastModified >= \"2023-01-15T08:30:00Z\"",
+		},
+		{
+			name:  "and chain",
+			query: Query().Where(FieldContentType).Eq("text/javascript").And("owner").Ne("bob"),
+			want:  "WHERE `Content-Type` = \"text/javascript\" AND `owner` != \"bob\"",
+		},
+		{
+			name:  "or then and keeps build order",
+			query: Query().Where("a").Eq("1").Or("b").Eq("2").And("c").Lt(3),
+			want:  "WHERE (`a` = \"1\" OR `b` = \"2\") AND `c` < 3",
+		},
+		{
+			name:  "grouped sub-query",
+			query: Query().Where("a").Eq("1").AndGroup(Query().Where("b").Le(2.5).Or("c").Eq("x")),
+			want:  "WHERE `a` = \"1\" AND (`b` <= 2.5 OR `c` = \"x\")",
+		},
+		{
+			name:  "escapes quotes and backslashes",
+			query: Query().Where("name").Eq(`say "hi" \o/`),
+			want:  `WHERE ` + "`name`" + ` = "say \"hi\" \\o/"`,
+		},
+		{
+			name:    "empty query",
+			query:   Query(),
+			wantErr: true,
+		},
+		{
+			name:    "empty field",
+			query:   Query().Where("").Eq("x"),
+			wantErr: true,
+		},
+		{
+			name:    "unknown built-in field",
+			query:   Query().Where("$bogus").Eq("x"),
+			wantErr: true,
+		},
+		{
+			name:    "backtick in field",
+			query:   Query().Where("a`b").Eq("x"),
+			wantErr: true,
+		},
+		{
+			name:    "control character in value",
+			query:   Query().Where("a").Eq("line\nbreak"),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported value type",
+			query:   Query().Where("a").Eq(true),
+			wantErr: true,
+		},
+		{
+			name:    "non-finite number",
+			query:   Query().Where(FieldSize).Gt(math.Inf(1)),
+			wantErr: true,
+		},
+		{
+			name:    "and before where",
+			query:   Query().And("a").Eq("x"),
+			wantErr: true,
+		},
+		{
+			name:    "where twice",
+			query:   Query().Where("a").Eq("x").Where("b").Eq("y"),
+			wantErr: true,
+		},
+		{
+			name:    "error in sub-query propagates",
+			query:   Query().Where("a").Eq("x").OrGroup(Query().Where("").Eq("y")),
+			wantErr: true,
+		},
+		{
+			name:    "error is sticky",
+			query:   Query().Where("").Eq("x").And("b").Eq("y"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Build()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidQuery) {
+					t.Errorf("Build() error = %v, want ErrInvalidQuery", err)
+				}
+				if tt.query.String() != "" {
+					t.Errorf("String() = %q for invalid query, want empty", tt.query.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryBuilder_immutable(t *testing.T) {
+	base := Query().Where("a").Eq("1")
+	left := base.And("b").Eq("2")
+	right := base.Or("c").Eq("3")
+
+	if base.String() != "WHERE `a` = \"1\"" {
+		t.Errorf("base query was modified: %s", base)
+	}
+	if left.String() == right.String() {
+		t.Errorf("derived queries share state: %s", left)
+	}
+}
+
+func TestWithQueryBuilder_invalidFailsRequest(t *testing.T) {
+	client := s3.New(s3.Options{
+		Region:       "auto",
+		BaseEndpoint: aws.String("http://127.0.0.1:0"),
+	})
+
+	_, err := client.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+	}, WithQueryBuilder(Query().Where("").Eq("x")))
+
+	if !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("ListObjectsV2() error = %v, want ErrInvalidQuery", err)
+	}
+}
@@ -83,6 +83,16 @@ func WithIfEtagMatches(etag string) func(*s3.Options) {
 	return WithHeader("If-Match", etag)
 }
 
+// WithIfEtagDoesNotMatch sets an ETag that the object must not match. On GET and HEAD
+// requests, a matching object results in a 304 Not Modified response.
+//
+// See the Tigris documentation[1] for more information.
+//
+// [1]: https://www.tigrisdata.com/docs/objects/conditionals/
+func WithIfEtagDoesNotMatch(etag string) func(*s3.Options) {
+	return WithHeader("If-None-Match", etag)
+}
+
 // WithModifiedSince lets you proceed with operation if object was modified after provided date (RFC1123).
 //
 // See the Tigris documentation[1] for more information.
@@ -18,8 +18,10 @@ func TestHeaderFunctionsAreValid(t *testing.T) {
 		{"WithHeader", func(o *s3.Options) { WithHeader("X-Test", "value")(o) }},
 		{"WithStaticReplicationRegions", func(o *s3.Options) { WithStaticReplicationRegions([]Region{FRA, SJC})(o) }},
 		{"WithQuery", func(o *s3.Options) { WithQuery("WHERE key = 'value'")(o) }},
+		{"WithQueryBuilder", func(o *s3.Options) { WithQueryBuilder(Query().Where("key").Eq("value"))(o) }},
 		{"WithCreateObjectIfNotExists", func(o *s3.Options) { WithCreateObjectIfNotExists()(o) }},
 		{"WithIfEtagMatches", func(o *s3.Options) { WithIfEtagMatches(`"abc"`)(o) }},
+		{"WithIfEtagDoesNotMatch", func(o *s3.Options) { WithIfEtagDoesNotMatch(`"abc"`)(o) }},
 		{"WithModifiedSince", func(o *s3.Options) { WithModifiedSince(time.Now())(o) }},
 		{"WithUnmodifiedSince", func(o *s3.Options) { WithUnmodifiedSince(time.Now())(o) }},
 		{"WithCompareAndSwap", func(o *s3.Options) { WithCompareAndSwap()(o) }},
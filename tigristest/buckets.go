@@ -0,0 +1,331 @@
+package tigristest
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bucket is the in-memory state of a single bucket.
+type bucket struct {
+	name    string
+	created time.Time
+
+	snapshotsEnabled bool
+	isForkParent     bool
+	sourceBucket     string
+	sourceSnapshot   string
+
+	objects   map[string]*object
+	snapshots []*snapshot
+	uploads   map[string]*upload
+}
+
+// snapshot is a point-in-time copy of a bucket's objects.
+type snapshot struct {
+	version string
+	name    string
+	created time.Time
+	objects map[string]*object
+}
+
+// findSnapshot returns the snapshot with the given version, or nil.
+func (b *bucket) findSnapshot(version string) *snapshot {
+	for _, snap := range b.snapshots {
+		if snap.version == version {
+			return snap
+		}
+	}
+	return nil
+}
+
+// takeSnapshot records the current objects of the bucket as a new snapshot.
+func (s *Server) takeSnapshot(b *bucket, name string) *snapshot {
+	snap := &snapshot{
+		version: strconv.FormatInt(s.nextSeq(), 10),
+		name:    name,
+		created: time.Now().UTC(),
+		objects: maps.Clone(b.objects),
+	}
+	b.snapshots = append(b.snapshots, snap)
+	return snap
+}
+
+// putBucket handles CreateBucket, including the Tigris snapshot and fork variants.
+func (s *Server) putBucket(w http.ResponseWriter, r *http.Request, name string) {
+	if len(r.URL.Query()) != 0 {
+		writeError(w, r, http.StatusNotImplemented, "NotImplemented", "bucket subresources are not implemented by tigristest")
+		return
+	}
+
+	if hdr := r.Header.Get("X-Tigris-Snapshot"); hdr != "" {
+		s.createSnapshot(w, r, name, hdr)
+		return
+	}
+
+	if _, ok := s.buckets[name]; ok {
+		writeError(w, r, http.StatusConflict, "BucketAlreadyOwnedByYou", "your previous request to create the named bucket succeeded and you already own it")
+		return
+	}
+
+	b := &bucket{
+		name:             name,
+		created:          time.Now().UTC(),
+		snapshotsEnabled: r.Header.Get("X-Tigris-Enable-Snapshot") == "true",
+		objects:          map[string]*object{},
+		uploads:          map[string]*upload{},
+	}
+
+	if source := r.Header.Get("X-Tigris-Fork-Source-Bucket"); source != "" {
+		src, ok := s.buckets[source]
+		if !ok {
+			writeError(w, r, http.StatusNotFound, "NoSuchBucket", "the fork source bucket does not exist")
+			return
+		}
+		if !src.snapshotsEnabled {
+			writeError(w, r, http.StatusBadRequest, "SnapshotsNotEnabled", "snapshots are not enabled for the fork source bucket")
+			return
+		}
+
+		var snap *snapshot
+		if version := r.Header.Get("X-Tigris-Snapshot-Version"); version != "" {
+			if snap = src.findSnapshot(version); snap == nil {
+				writeError(w, r, http.StatusBadRequest, "InvalidArgument", "the snapshot version does not exist")
+				return
+			}
+		} else {
+			snap = s.takeSnapshot(src, "")
+		}
+
+		src.isForkParent = true
+		b.snapshotsEnabled = true
+		b.sourceBucket = source
+		b.sourceSnapshot = snap.version
+		b.objects = maps.Clone(snap.objects)
+	}
+
+	s.buckets[name] = b
+
+	w.Header().Set("Location", "/"+name)
+	w.WriteHeader(http.StatusOK)
+}
+
+// createSnapshot handles a CreateBucket call carrying the X-Tigris-Snapshot header.
+func (s *Server) createSnapshot(w http.ResponseWriter, r *http.Request, name, hdr string) {
+	b, ok := s.buckets[name]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+		return
+	}
+	if !b.snapshotsEnabled {
+		writeError(w, r, http.StatusBadRequest, "SnapshotsNotEnabled", "snapshots are not enabled for this bucket")
+		return
+	}
+
+	var desc string
+	for part := range strings.SplitSeq(hdr, ";") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(part), "name="); ok {
+			unescaped, err := url.QueryUnescape(v)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "InvalidArgument", "the snapshot name is not URL-escaped")
+				return
+			}
+			desc = unescaped
+		}
+	}
+
+	snap := s.takeSnapshot(b, desc)
+
+	w.Header().Set("X-Tigris-Snapshot-Version", snap.version)
+	w.WriteHeader(http.StatusOK)
+}
+
+// headBucket handles HeadBucket and reports fork and snapshot metadata.
+func (s *Server) headBucket(w http.ResponseWriter, r *http.Request, name string) {
+	b, ok := s.buckets[name]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+		return
+	}
+
+	h := w.Header()
+	h.Set("X-Tigris-Enable-Snapshot", strconv.FormatBool(b.snapshotsEnabled))
+	h.Set("X-Tigris-Is-Fork-Parent", strconv.FormatBool(b.isForkParent))
+	if b.sourceBucket != "" {
+		h.Set("X-Tigris-Fork-Source-Bucket", b.sourceBucket)
+		h.Set("X-Tigris-Fork-Source-Bucket-Snapshot", b.sourceSnapshot)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// deleteBucket handles DeleteBucket.
+func (s *Server) deleteBucket(w http.ResponseWriter, r *http.Request, name string) {
+	b, ok := s.buckets[name]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+		return
+	}
+	if len(b.objects) != 0 {
+		writeError(w, r, http.StatusConflict, "BucketNotEmpty", "the bucket you tried to delete is not empty")
+		return
+	}
+
+	delete(s.buckets, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listAllMyBucketsResult is the XML body of a ListBuckets response.
+type listAllMyBucketsResult struct {
+	XMLName           xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner             owner          `xml:"Owner"`
+	Buckets           []bucketResult `xml:"Buckets>Bucket"`
+	ContinuationToken string         `xml:"ContinuationToken,omitempty"`
+	Prefix            string         `xml:"Prefix,omitempty"`
+}
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type bucketResult struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+// listBuckets handles ListBuckets, including the max-buckets, prefix and
+// continuation-token parameters.
+func (s *Server) listBuckets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+
+	names := slices.Sorted(maps.Keys(s.buckets))
+	entries := make([]bucketResult, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		entries = append(entries, bucketResult{
+			Name:         name,
+			CreationDate: formatTime(s.buckets[name].created),
+		})
+	}
+
+	page, next, err := paginate(entries, q.Get("continuation-token"), q.Get("max-buckets"), func(b bucketResult) string { return b.Name })
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", err.Error())
+		return
+	}
+
+	writeXML(w, http.StatusOK, listAllMyBucketsResult{
+		Owner:             owner{ID: "tigristest", DisplayName: "tigristest"},
+		Buckets:           page,
+		ContinuationToken: next,
+		Prefix:            prefix,
+	})
+}
+
+// listSnapshots handles a ListBuckets call carrying the X-Tigris-Snapshot header.
+//
+// Each snapshot is reported as a bucket entry named "<version>; name=<escaped description>",
+// or just "<version>" for snapshots without a description.
+func (s *Server) listSnapshots(w http.ResponseWriter, r *http.Request, name string) {
+	b, ok := s.buckets[name]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchBucket", "the specified bucket does not exist")
+		return
+	}
+	if !b.snapshotsEnabled {
+		writeError(w, r, http.StatusBadRequest, "SnapshotsNotEnabled", "snapshots are not enabled for this bucket")
+		return
+	}
+
+	entries := make([]bucketResult, 0, len(b.snapshots))
+	for _, snap := range b.snapshots {
+		entryName := snap.version
+		if snap.name != "" {
+			entryName += "; name=" + url.QueryEscape(snap.name)
+		}
+		entries = append(entries, bucketResult{
+			Name:         entryName,
+			CreationDate: formatTime(snap.created),
+		})
+	}
+
+	q := r.URL.Query()
+	page, next, err := paginate(entries, q.Get("continuation-token"), q.Get("max-buckets"), func(b bucketResult) string { return b.Name })
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", err.Error())
+		return
+	}
+
+	writeXML(w, http.StatusOK, listAllMyBucketsResult{
+		Owner:             owner{ID: "tigristest", DisplayName: "tigristest"},
+		Buckets:           page,
+		ContinuationToken: next,
+	})
+}
+
+// paginate returns the page of sorted entries following the opaque token, limited to
+// maxStr entries (default 1000), along with the token for the next page.
+func paginate[T any](entries []T, token, maxStr string, keyOf func(T) string) ([]T, string, error) {
+	limit := 1000
+	if maxStr != "" {
+		n, err := strconv.Atoi(maxStr)
+		if err != nil || n < 0 {
+			return nil, "", errInvalidMax
+		}
+		limit = min(n, 1000)
+	}
+
+	start := 0
+	if token != "" {
+		marker, err := decodeToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+		start = len(entries)
+		for i, e := range entries {
+			if keyOf(e) > marker {
+				start = i
+				break
+			}
+		}
+	}
+
+	entries = entries[start:]
+	if len(entries) <= limit {
+		return entries, "", nil
+	}
+
+	page := entries[:limit]
+	if limit == 0 {
+		return page, "", nil
+	}
+	return page, encodeToken(keyOf(page[len(page)-1])), nil
+}
+
+// encodeToken turns a listing marker into an opaque continuation token.
+func encodeToken(marker string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(marker))
+}
+
+// decodeToken reverses encodeToken.
+func decodeToken(token string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", errInvalidToken
+	}
+	return string(data), nil
+}
+
+// formatTime formats t the way S3 formats timestamps in XML bodies.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,276 @@
+package tigristest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// upload is an in-progress multipart upload.
+type upload struct {
+	id        string
+	key       string
+	initiated time.Time
+	headers   http.Header
+	parts     map[int32]*part
+}
+
+// part is a single uploaded part of a multipart upload.
+type part struct {
+	number       int32
+	data         []byte
+	etag         string
+	lastModified time.Time
+}
+
+// findUpload returns the upload with the given ID for key, writing a NoSuchUpload
+// error if it does not exist.
+func findUpload(w http.ResponseWriter, r *http.Request, b *bucket, key string) (*upload, bool) {
+	u, ok := b.uploads[r.URL.Query().Get("uploadId")]
+	if !ok || u.key != key {
+		writeError(w, r, http.StatusNotFound, "NoSuchUpload", "the specified multipart upload does not exist")
+		return nil, false
+	}
+	return u, true
+}
+
+// initiateMultipartUploadResult is the XML body of a CreateMultipartUpload response.
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+// createMultipartUpload handles CreateMultipartUpload.
+func (s *Server) createMultipartUpload(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	if !noWriteConditions(w, r) {
+		return
+	}
+
+	u := &upload{
+		id:        strconv.FormatInt(s.nextSeq(), 36),
+		key:       key,
+		initiated: time.Now().UTC(),
+		headers:   r.Header.Clone(),
+		parts:     map[int32]*part{},
+	}
+	b.uploads[u.id] = u
+
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{
+		Bucket:   b.name,
+		Key:      key,
+		UploadID: u.id,
+	})
+}
+
+// noWriteConditions rejects If-Match and If-None-Match on the calls that start a
+// multipart upload and upload its parts, as S3 only evaluates them when the upload
+// is completed. It reports whether r has none.
+func noWriteConditions(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("If-Match") != "" || r.Header.Get("If-None-Match") != "" {
+		writeError(w, r, http.StatusNotImplemented, "NotImplemented", "a header you provided implies functionality that is not implemented")
+		return false
+	}
+	return true
+}
+
+// uploadPart handles UploadPart.
+func (s *Server) uploadPart(w http.ResponseWriter, r *http.Request, b *bucket, key string, body []byte) {
+	if !noWriteConditions(w, r) {
+		return
+	}
+
+	u, ok := findUpload(w, r, b, key)
+	if !ok {
+		return
+	}
+
+	n, err := strconv.ParseInt(r.URL.Query().Get("partNumber"), 10, 32)
+	if err != nil || n < 1 || n > 10000 {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", "part number must be an integer between 1 and 10000")
+		return
+	}
+
+	sum := md5.Sum(body)
+	p := &part{
+		number:       int32(n),
+		data:         body,
+		etag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		lastModified: time.Now().UTC(),
+	}
+	u.parts[p.number] = p
+
+	w.Header().Set("ETag", p.etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+// completeMultipartUpload is the XML body of a CompleteMultipartUpload request.
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int32  `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+// completeMultipartUploadResult is the XML body of a CompleteMultipartUpload response.
+type completeMultipartUploadResult struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+// completeMultipartUpload handles CompleteMultipartUpload. The ETag of the resulting
+// object is computed the same way S3 does: the MD5 of the concatenated part MD5s,
+// followed by a dash and the number of parts.
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request, b *bucket, key string, body []byte) {
+	u, ok := findUpload(w, r, b, key)
+	if !ok {
+		return
+	}
+
+	var req completeMultipartUpload
+	if err := xml.Unmarshal(body, &req); err != nil || len(req.Parts) == 0 {
+		writeError(w, r, http.StatusBadRequest, "MalformedXML", "the XML you provided was not well-formed")
+		return
+	}
+
+	var data bytes.Buffer
+	var sums []byte
+	var prev int32
+	for _, rp := range req.Parts {
+		if rp.PartNumber <= prev {
+			writeError(w, r, http.StatusBadRequest, "InvalidPartOrder", "the list of parts was not in ascending order")
+			return
+		}
+		prev = rp.PartNumber
+
+		p, ok := u.parts[rp.PartNumber]
+		if !ok || !etagMatches(rp.ETag, p.etag) {
+			writeError(w, r, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d could not be found or its entity tag did not match", rp.PartNumber))
+			return
+		}
+
+		data.Write(p.data)
+		sum, _ := hex.DecodeString(strings.Trim(p.etag, `"`))
+		sums = append(sums, sum...)
+	}
+
+	if !checkWriteConditions(w, r, b.objects[key]) {
+		return
+	}
+
+	obj := newObject(key, data.Bytes(), u.headers)
+	total := md5.Sum(sums)
+	obj.etag = fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(total[:]), len(req.Parts))
+	b.objects[key] = obj
+	delete(b.uploads, u.id)
+
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Bucket: b.name,
+		Key:    key,
+		ETag:   obj.etag,
+	})
+}
+
+// abortMultipartUpload handles AbortMultipartUpload.
+func (s *Server) abortMultipartUpload(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	u, ok := findUpload(w, r, b, key)
+	if !ok {
+		return
+	}
+
+	delete(b.uploads, u.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listPartsResult is the XML body of a ListParts response.
+type listPartsResult struct {
+	XMLName  xml.Name     `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListPartsResult"`
+	Bucket   string       `xml:"Bucket"`
+	Key      string       `xml:"Key"`
+	UploadID string       `xml:"UploadId"`
+	Parts    []partResult `xml:"Part"`
+}
+
+type partResult struct {
+	PartNumber   int32  `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+// listParts handles ListParts. All parts are returned in a single page.
+func (s *Server) listParts(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	u, ok := findUpload(w, r, b, key)
+	if !ok {
+		return
+	}
+
+	result := listPartsResult{
+		Bucket:   b.name,
+		Key:      key,
+		UploadID: u.id,
+	}
+	for _, n := range slices.Sorted(maps.Keys(u.parts)) {
+		p := u.parts[n]
+		result.Parts = append(result.Parts, partResult{
+			PartNumber:   p.number,
+			LastModified: formatTime(p.lastModified),
+			ETag:         p.etag,
+			Size:         int64(len(p.data)),
+		})
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+// listMultipartUploadsResult is the XML body of a ListMultipartUploads response.
+type listMultipartUploadsResult struct {
+	XMLName xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListMultipartUploadsResult"`
+	Bucket  string         `xml:"Bucket"`
+	Prefix  string         `xml:"Prefix"`
+	Uploads []uploadResult `xml:"Upload"`
+}
+
+type uploadResult struct {
+	Key       string `xml:"Key"`
+	UploadID  string `xml:"UploadId"`
+	Initiated string `xml:"Initiated"`
+}
+
+// listMultipartUploads handles ListMultipartUploads. All matching uploads are returned
+// in a single page.
+func (s *Server) listMultipartUploads(w http.ResponseWriter, r *http.Request, b *bucket) {
+	prefix := r.URL.Query().Get("prefix")
+
+	result := listMultipartUploadsResult{
+		Bucket: b.name,
+		Prefix: prefix,
+	}
+
+	uploads := slices.SortedFunc(maps.Values(b.uploads), func(a, b *upload) int {
+		return strings.Compare(a.key+"\x00"+a.id, b.key+"\x00"+b.id)
+	})
+	for _, u := range uploads {
+		if !strings.HasPrefix(u.key, prefix) {
+			continue
+		}
+		result.Uploads = append(result.Uploads, uploadResult{
+			Key:       u.key,
+			UploadID:  u.id,
+			Initiated: formatTime(u.initiated),
+		})
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
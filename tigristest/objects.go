@@ -0,0 +1,623 @@
+package tigristest
+
+import (
+	"cmp"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidMax   = errors.New("max-keys must be a non-negative integer")
+	errInvalidToken = errors.New("the continuation token provided is incorrect")
+)
+
+// object is a stored object. Objects are never mutated after they are stored, so
+// snapshots can share them with the live bucket.
+type object struct {
+	key                string
+	data               []byte
+	etag               string
+	contentType        string
+	contentDisposition string
+	cacheControl       string
+	contentEncoding    string
+	contentLanguage    string
+	expires            string
+	metadata           map[string]string
+	lastModified       time.Time
+}
+
+// newObject builds an object from data and the content headers of r.
+func newObject(key string, data []byte, h http.Header) *object {
+	sum := md5.Sum(data)
+	obj := &object{
+		key:          key,
+		data:         data,
+		etag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		lastModified: time.Now().UTC(),
+	}
+	obj.setContentHeaders(h)
+	return obj
+}
+
+// setContentHeaders copies the user-settable content headers and metadata from h.
+func (o *object) setContentHeaders(h http.Header) {
+	o.contentType = h.Get("Content-Type")
+	o.contentDisposition = h.Get("Content-Disposition")
+	o.cacheControl = h.Get("Cache-Control")
+	o.contentEncoding = strings.TrimSpace(strings.ReplaceAll(h.Get("Content-Encoding"), "aws-chunked", ""))
+	o.contentEncoding = strings.Trim(o.contentEncoding, ", ")
+	o.contentLanguage = h.Get("Content-Language")
+	o.expires = h.Get("Expires")
+	o.metadata = map[string]string{}
+	for name, values := range h {
+		if k, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-"); ok {
+			o.metadata[k] = strings.Join(values, ",")
+		}
+	}
+}
+
+// writeHeaders writes the standard object response headers.
+func (o *object) writeHeaders(h http.Header) {
+	h.Set("ETag", o.etag)
+	h.Set("Last-Modified", o.lastModified.Format(http.TimeFormat))
+	h.Set("Content-Type", cmp.Or(o.contentType, "application/octet-stream"))
+	h.Set("Accept-Ranges", "bytes")
+	if o.contentDisposition != "" {
+		h.Set("Content-Disposition", o.contentDisposition)
+	}
+	if o.cacheControl != "" {
+		h.Set("Cache-Control", o.cacheControl)
+	}
+	if o.contentEncoding != "" {
+		h.Set("Content-Encoding", o.contentEncoding)
+	}
+	if o.contentLanguage != "" {
+		h.Set("Content-Language", o.contentLanguage)
+	}
+	if o.expires != "" {
+		h.Set("Expires", o.expires)
+	}
+	for k, v := range o.metadata {
+		h.Set("X-Amz-Meta-"+k, v)
+	}
+}
+
+// objectsFor returns the objects visible to r, honouring X-Tigris-Snapshot-Version.
+func objectsFor(w http.ResponseWriter, r *http.Request, b *bucket) (map[string]*object, bool) {
+	version := r.Header.Get("X-Tigris-Snapshot-Version")
+	if version == "" {
+		return b.objects, true
+	}
+
+	snap := b.findSnapshot(version)
+	if snap == nil {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", "the snapshot version does not exist")
+		return nil, false
+	}
+	return snap.objects, true
+}
+
+// checkWriteConditions evaluates If-Match and If-None-Match for a write to key. An
+// If-Match value of `""` means the object must not exist yet. It reports whether the
+// write may proceed and writes an error response if not.
+func checkWriteConditions(w http.ResponseWriter, r *http.Request, existing *object) bool {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if ifMatch == `""` {
+			if existing != nil {
+				writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+				return false
+			}
+		} else if existing == nil {
+			writeError(w, r, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+			return false
+		} else if !etagMatches(ifMatch, existing.etag) {
+			writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+			return false
+		}
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && existing != nil {
+		if ifNoneMatch == "*" || etagMatches(ifNoneMatch, existing.etag) {
+			writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+			return false
+		}
+	}
+
+	if since := r.Header.Get("If-Unmodified-Since"); since != "" && existing != nil {
+		if t, err := http.ParseTime(since); err == nil && existing.lastModified.Truncate(time.Second).After(t) {
+			writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+			return false
+		}
+	}
+
+	return true
+}
+
+// etagMatches reports whether any entity tag in the comma-separated list matches etag.
+func etagMatches(list, etag string) bool {
+	for candidate := range strings.SplitSeq(list, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || strings.Trim(candidate, `"`) == strings.Trim(etag, `"`) {
+			return true
+		}
+	}
+	return false
+}
+
+// putObject handles PutObject.
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, b *bucket, key string, body []byte) {
+	if !checkWriteConditions(w, r, b.objects[key]) {
+		return
+	}
+
+	if digest := r.Header.Get("Content-MD5"); digest != "" {
+		sum := md5.Sum(body)
+		if digest != base64.StdEncoding.EncodeToString(sum[:]) {
+			writeError(w, r, http.StatusBadRequest, "BadDigest", "the Content-MD5 you specified did not match what was received")
+			return
+		}
+	}
+
+	obj := newObject(key, body, r.Header)
+	b.objects[key] = obj
+
+	w.Header().Set("ETag", obj.etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+// getObject handles GetObject and HeadObject, including range and conditional requests.
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	objects, ok := objectsFor(w, r, b)
+	if !ok {
+		return
+	}
+
+	obj, ok := objects[key]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		return
+	}
+
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !etagMatches(ifMatch, obj.etag) {
+		writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+		return
+	}
+	if since := r.Header.Get("If-Unmodified-Since"); since != "" && r.Header.Get("If-Match") == "" {
+		if t, err := http.ParseTime(since); err == nil && obj.lastModified.Truncate(time.Second).After(t) {
+			writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+			return
+		}
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if etagMatches(ifNoneMatch, obj.etag) {
+			notModified(w, obj)
+			return
+		}
+	} else if since := r.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil && !obj.lastModified.Truncate(time.Second).After(t) {
+			notModified(w, obj)
+			return
+		}
+	}
+
+	h := w.Header()
+	obj.writeHeaders(h)
+
+	q := r.URL.Query()
+	for param, header := range responseOverrides {
+		if v := q.Get(param); v != "" {
+			h.Set(header, v)
+		}
+	}
+
+	size := int64(len(obj.data))
+	data := obj.data
+	status := http.StatusOK
+
+	if rng := r.Header.Get("Range"); rng != "" {
+		start, end, err := parseRange(rng, size)
+		if err != nil {
+			h.Del("ETag")
+			h.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			writeError(w, r, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "the requested range is not satisfiable")
+			return
+		}
+		data = obj.data[start : end+1]
+		status = http.StatusPartialContent
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
+
+// responseOverrides maps GetObject response-* query parameters to the headers they override.
+var responseOverrides = map[string]string{
+	"response-cache-control":       "Cache-Control",
+	"response-content-disposition": "Content-Disposition",
+	"response-content-encoding":    "Content-Encoding",
+	"response-content-language":    "Content-Language",
+	"response-content-type":        "Content-Type",
+	"response-expires":             "Expires",
+}
+
+// notModified writes a 304 Not Modified response for obj.
+func notModified(w http.ResponseWriter, obj *object) {
+	w.Header().Set("ETag", obj.etag)
+	w.Header().Set("Last-Modified", obj.lastModified.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// parseRange parses a single-range "bytes=" Range header against an object of the
+// given size, returning the inclusive start and end offsets.
+func parseRange(header string, size int64) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, errors.New("unsupported range")
+	}
+
+	startStr, endStr, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, errors.New("malformed range")
+	}
+
+	if startStr == "" {
+		n, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, errors.New("malformed suffix range")
+		}
+		return max(size-n, 0), size - 1, nil
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, errors.New("range start out of bounds")
+	}
+
+	end := size - 1
+	if endStr != "" {
+		end, err = strconv.ParseInt(endStr, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, errors.New("malformed range end")
+		}
+		end = min(end, size-1)
+	}
+
+	return start, end, nil
+}
+
+// deleteObject handles DeleteObject.
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	existing := b.objects[key]
+	if r.Header.Get("If-Match") != "" && existing == nil {
+		writeError(w, r, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		return
+	}
+	if !checkWriteConditions(w, r, existing) {
+		return
+	}
+
+	delete(b.objects, key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteRequest is the XML body of a DeleteObjects request.
+type deleteRequest struct {
+	Objects []struct {
+		Key       string `xml:"Key"`
+		VersionID string `xml:"VersionId"`
+	} `xml:"Object"`
+	Quiet bool `xml:"Quiet"`
+}
+
+// deleteResult is the XML body of a DeleteObjects response.
+type deleteResult struct {
+	XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+	Deleted []deletedResult `xml:"Deleted"`
+	Errors  []deleteError   `xml:"Error"`
+}
+
+type deletedResult struct {
+	Key string `xml:"Key"`
+}
+
+type deleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// deleteObjects handles DeleteObjects.
+func (s *Server) deleteObjects(w http.ResponseWriter, r *http.Request, b *bucket, body []byte) {
+	var req deleteRequest
+	if err := xml.Unmarshal(body, &req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "MalformedXML", "the XML you provided was not well-formed")
+		return
+	}
+	if len(req.Objects) > 1000 {
+		writeError(w, r, http.StatusBadRequest, "MalformedXML", "a DeleteObjects request can contain at most 1000 keys")
+		return
+	}
+
+	var result deleteResult
+	for _, o := range req.Objects {
+		delete(b.objects, o.Key)
+		if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedResult{Key: o.Key})
+		}
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+// copyObjectResult is the XML body of a CopyObject response.
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult"`
+	ETag         string   `xml:"ETag"`
+	LastModified string   `xml:"LastModified"`
+}
+
+// copyObject handles CopyObject, including Tigris in-place renames.
+func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, dst *bucket, key string) {
+	source, err := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", "the copy source is not URL-escaped")
+		return
+	}
+	source, _, _ = strings.Cut(source, "?versionId=")
+	srcBucketName, srcKey, ok := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	if !ok || srcKey == "" {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", "the copy source must be of the form bucket/key")
+		return
+	}
+
+	src, ok := s.buckets[srcBucketName]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchBucket", "the copy source bucket does not exist")
+		return
+	}
+
+	objects, ok := objectsFor(w, r, src)
+	if !ok {
+		return
+	}
+
+	srcObj, ok := objects[srcKey]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		return
+	}
+
+	if ifMatch := r.Header.Get("X-Amz-Copy-Source-If-Match"); ifMatch != "" && !etagMatches(ifMatch, srcObj.etag) {
+		writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+		return
+	}
+	if ifNoneMatch := r.Header.Get("X-Amz-Copy-Source-If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, srcObj.etag) {
+		writeError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the pre-conditions you specified did not hold")
+		return
+	}
+
+	if !checkWriteConditions(w, r, dst.objects[key]) {
+		return
+	}
+
+	rename := r.Header.Get("X-Tigris-Rename") == "true"
+	if rename && src != dst {
+		writeError(w, r, http.StatusBadRequest, "InvalidRequest", "objects can only be renamed within a bucket")
+		return
+	}
+
+	obj := *srcObj
+	obj.key = key
+	obj.lastModified = time.Now().UTC()
+	if strings.EqualFold(r.Header.Get("X-Amz-Metadata-Directive"), "REPLACE") {
+		obj.setContentHeaders(r.Header)
+	} else {
+		obj.metadata = maps.Clone(srcObj.metadata)
+	}
+
+	dst.objects[key] = &obj
+	if rename && srcKey != key {
+		delete(src.objects, srcKey)
+	}
+
+	writeXML(w, http.StatusOK, copyObjectResult{
+		ETag:         obj.etag,
+		LastModified: formatTime(obj.lastModified),
+	})
+}
+
+// listVersionsResult is the XML body of a ListObjectVersions response.
+type listVersionsResult struct {
+	XMLName  xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult"`
+	Name     string          `xml:"Name"`
+	Prefix   string          `xml:"Prefix"`
+	Versions []versionResult `xml:"Version"`
+}
+
+type versionResult struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+// listObjectVersions handles ListObjectVersions. Buckets are not versioned, so every
+// object has a single version with the ID "null", as in S3. All matching versions
+// are returned in a single page.
+func (s *Server) listObjectVersions(w http.ResponseWriter, r *http.Request, b *bucket) {
+	prefix := r.URL.Query().Get("prefix")
+
+	result := listVersionsResult{
+		Name:   b.name,
+		Prefix: prefix,
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(b.objects)) {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		obj := b.objects[key]
+		result.Versions = append(result.Versions, versionResult{
+			Key:          key,
+			VersionID:    "null",
+			IsLatest:     true,
+			LastModified: formatTime(obj.lastModified),
+			ETag:         obj.etag,
+			Size:         int64(len(obj.data)),
+		})
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+// listBucketResult is the XML body of a ListObjectsV2 response.
+type listBucketResult struct {
+	XMLName               xml.Name         `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	Delimiter             string           `xml:"Delimiter,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	MaxKeys               int              `xml:"MaxKeys"`
+	KeyCount              int              `xml:"KeyCount"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	Contents              []objectResult   `xml:"Contents"`
+	CommonPrefixes        []commonPrefixes `xml:"CommonPrefixes"`
+}
+
+type objectResult struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefixes struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listObjectsV2 handles ListObjectsV2, including delimiters, pagination and
+// X-Tigris-Query filtering.
+func (s *Server) listObjectsV2(w http.ResponseWriter, r *http.Request, b *bucket) {
+	objects, ok := objectsFor(w, r, b)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+	delimiter := q.Get("delimiter")
+	startAfter := q.Get("start-after")
+
+	maxKeys := 1000
+	if v := q.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, r, http.StatusBadRequest, "InvalidArgument", errInvalidMax.Error())
+			return
+		}
+		maxKeys = min(n, 1000)
+	}
+
+	marker := startAfter
+	if token := q.Get("continuation-token"); token != "" {
+		m, err := decodeToken(token)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "InvalidArgument", err.Error())
+			return
+		}
+		marker = m
+	}
+
+	var filter func(*object) bool
+	if raw := r.Header.Get("X-Tigris-Query"); raw != "" {
+		expr, err := parseQuery(raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "InvalidArgument", fmt.Sprintf("invalid X-Tigris-Query: %v", err))
+			return
+		}
+		filter = expr.eval
+	}
+
+	result := listBucketResult{
+		Name:              b.name,
+		Prefix:            prefix,
+		Delimiter:         delimiter,
+		StartAfter:        startAfter,
+		MaxKeys:           maxKeys,
+		ContinuationToken: q.Get("continuation-token"),
+	}
+
+	var last, lastPrefix string
+	for _, key := range slices.Sorted(maps.Keys(objects)) {
+		if !strings.HasPrefix(key, prefix) || key <= marker {
+			continue
+		}
+		if delimiter != "" && strings.HasSuffix(marker, delimiter) && strings.HasPrefix(key, marker) {
+			continue
+		}
+
+		obj := objects[key]
+		if filter != nil && !filter(obj) {
+			continue
+		}
+
+		entry := key
+		isPrefix := false
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				entry = key[:len(prefix)+i+len(delimiter)]
+				isPrefix = true
+				if entry == lastPrefix {
+					continue
+				}
+			}
+		}
+
+		if result.KeyCount == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+
+		if isPrefix {
+			lastPrefix = entry
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefixes{Prefix: entry})
+		} else {
+			result.Contents = append(result.Contents, objectResult{
+				Key:          key,
+				LastModified: formatTime(obj.lastModified),
+				ETag:         obj.etag,
+				Size:         int64(len(obj.data)),
+				StorageClass: "STANDARD",
+			})
+		}
+		result.KeyCount++
+		last = entry
+	}
+
+	if result.IsTruncated && last != "" {
+		result.NextContinuationToken = encodeToken(last)
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
@@ -0,0 +1,235 @@
+package tigristest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// postResponse is the XML body of a form upload answered with success_action_status 201.
+type postResponse struct {
+	XMLName  xml.Name `xml:"PostResponse"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// postPolicy is a decoded POST policy document.
+type postPolicy struct {
+	Expiration time.Time         `json:"expiration"`
+	Conditions []json.RawMessage `json:"conditions"`
+}
+
+// errPolicy is a form upload that doesn't satisfy its policy.
+type errPolicy struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *errPolicy) Error() string {
+	return e.message
+}
+
+// postObject handles a browser form upload (POST Object). The policy's expiration and
+// conditions are enforced, including the rule that every form field must be covered
+// by a condition, but like every other request its signature is not verified.
+func (s *Server) postObject(w http.ResponseWriter, r *http.Request, b *bucket, body []byte) {
+	fields, filename, data, err := parseForm(r, body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "MalformedPOSTRequest", err.Error())
+		return
+	}
+
+	if fields["key"] == "" {
+		writeError(w, r, http.StatusBadRequest, "InvalidArgument", "bucket POST must contain a field named 'key'")
+		return
+	}
+
+	if err := checkPolicy(b.name, fields, int64(len(data))); err != nil {
+		var pe *errPolicy
+		errors.As(err, &pe)
+		writeError(w, r, pe.status, pe.code, pe.message)
+		return
+	}
+
+	key := strings.ReplaceAll(fields["key"], "${filename}", filename)
+
+	h := http.Header{}
+	for name, value := range fields {
+		h.Set(name, value)
+	}
+
+	obj := newObject(key, data, h)
+	b.objects[key] = obj
+
+	location := (&url.URL{Scheme: "http", Host: r.Host, Path: "/" + b.name + "/" + key}).String()
+	w.Header().Set("ETag", obj.etag)
+	w.Header().Set("Location", location)
+
+	if redirect := fields["success_action_redirect"]; redirect != "" {
+		if u, err := url.Parse(redirect); err == nil {
+			q := u.Query()
+			q.Set("bucket", b.name)
+			q.Set("key", key)
+			q.Set("etag", obj.etag)
+			u.RawQuery = q.Encode()
+
+			w.Header().Set("Location", u.String())
+			w.WriteHeader(http.StatusSeeOther)
+			return
+		}
+	}
+
+	switch fields["success_action_status"] {
+	case "200":
+		w.WriteHeader(http.StatusOK)
+	case "201":
+		writeXML(w, http.StatusCreated, postResponse{
+			Location: location,
+			Bucket:   b.name,
+			Key:      key,
+			ETag:     obj.etag,
+		})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// parseForm reads the fields of a multipart/form-data upload, keyed by lower-case
+// name, up to the file field, which must come last.
+func parseForm(r *http.Request, body []byte) (fields map[string]string, filename string, data []byte, err error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("can't parse Content-Type: %w", err)
+	}
+
+	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	fields = map[string]string{}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, "", nil, errors.New("POST requires exactly one file upload per request")
+		}
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		if part.FormName() == "file" {
+			return fields, part.FileName(), value, nil
+		}
+		fields[strings.ToLower(part.FormName())] = string(value)
+	}
+}
+
+// checkPolicy checks the form fields and the size of the uploaded file against the
+// base64-encoded policy in the policy field.
+func checkPolicy(bucket string, fields map[string]string, size int64) error {
+	denied := func(format string, args ...any) error {
+		return &errPolicy{http.StatusForbidden, "AccessDenied", "Invalid according to Policy: " + fmt.Sprintf(format, args...)}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(fields["policy"])
+	if err != nil {
+		return &errPolicy{http.StatusBadRequest, "InvalidPolicyDocument", "the policy is not valid base64"}
+	}
+
+	var policy postPolicy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return &errPolicy{http.StatusBadRequest, "InvalidPolicyDocument", "the policy is not a valid document: " + err.Error()}
+	}
+
+	if time.Now().After(policy.Expiration) {
+		return denied("Policy expired.")
+	}
+
+	values := map[string]string{"bucket": bucket}
+	for name, value := range fields {
+		values[name] = value
+	}
+
+	covered := map[string]bool{}
+
+	for _, cond := range policy.Conditions {
+		var exact map[string]string
+		if err := json.Unmarshal(cond, &exact); err == nil {
+			for name, want := range exact {
+				name = strings.ToLower(name)
+				if values[name] != want {
+					return denied("Policy Condition failed: [\"eq\", \"$%s\", %q]", name, want)
+				}
+				covered[name] = true
+			}
+			continue
+		}
+
+		var op []json.RawMessage
+		if err := json.Unmarshal(cond, &op); err != nil || len(op) != 3 {
+			return &errPolicy{http.StatusBadRequest, "InvalidPolicyDocument", "malformed condition " + string(cond)}
+		}
+
+		var kind string
+		json.Unmarshal(op[0], &kind)
+
+		if kind == "content-length-range" {
+			var lo, hi int64
+			if json.Unmarshal(op[1], &lo) != nil || json.Unmarshal(op[2], &hi) != nil {
+				return &errPolicy{http.StatusBadRequest, "InvalidPolicyDocument", "malformed condition " + string(cond)}
+			}
+			if size > hi {
+				return &errPolicy{http.StatusBadRequest, "EntityTooLarge", "your proposed upload exceeds the maximum allowed size"}
+			}
+			if size < lo {
+				return &errPolicy{http.StatusBadRequest, "EntityTooSmall", "your proposed upload is smaller than the minimum allowed size"}
+			}
+			continue
+		}
+
+		var field, want string
+		if json.Unmarshal(op[1], &field) != nil || json.Unmarshal(op[2], &want) != nil || !strings.HasPrefix(field, "$") {
+			return &errPolicy{http.StatusBadRequest, "InvalidPolicyDocument", "malformed condition " + string(cond)}
+		}
+		name := strings.ToLower(field[1:])
+
+		switch kind {
+		case "eq":
+			if values[name] != want {
+				return denied("Policy Condition failed: [\"eq\", %q, %q]", field, want)
+			}
+		case "starts-with":
+			if !strings.HasPrefix(values[name], want) {
+				return denied("Policy Condition failed: [\"starts-with\", %q, %q]", field, want)
+			}
+		default:
+			return &errPolicy{http.StatusBadRequest, "InvalidPolicyDocument", "unknown condition " + kind}
+		}
+		covered[name] = true
+	}
+
+	for name := range fields {
+		switch {
+		case name == "policy", name == "x-amz-signature", strings.HasPrefix(name, "x-ignore-"):
+		case !covered[name]:
+			return denied("Extra input fields: %s", name)
+		}
+	}
+
+	return nil
+}
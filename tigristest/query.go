@@ -0,0 +1,322 @@
+package tigristest
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// queryExpr is a parsed X-Tigris-Query filter expression.
+type queryExpr interface {
+	eval(*object) bool
+}
+
+type andExpr struct{ lhs, rhs queryExpr }
+
+func (e andExpr) eval(o *object) bool { return e.lhs.eval(o) && e.rhs.eval(o) }
+
+type orExpr struct{ lhs, rhs queryExpr }
+
+func (e orExpr) eval(o *object) bool { return e.lhs.eval(o) || e.rhs.eval(o) }
+
+type notExpr struct{ inner queryExpr }
+
+func (e notExpr) eval(o *object) bool { return !e.inner.eval(o) }
+
+// comparison compares an object field against a literal value.
+type comparison struct {
+	field string
+	op    string
+	value literal
+}
+
+// literal is a string or numeric query literal.
+type literal struct {
+	str      string
+	num      float64
+	isNumber bool
+}
+
+func (c comparison) eval(o *object) bool {
+	var result int
+
+	switch c.field {
+	case "$size", "content-length":
+		if !c.value.isNumber {
+			return false
+		}
+		result = cmp.Compare(float64(len(o.data)), c.value.num)
+	case "$lastmodified", "last-modified":
+		t, err := time.Parse(time.RFC3339, c.value.str)
+		if err != nil {
+			return false
+		}
+		result = o.lastModified.Truncate(time.Second).Compare(t)
+	case "$key", "key":
+		result = strings.Compare(o.key, c.value.str)
+	case "$contenttype", "content-type":
+		result = strings.Compare(cmp.Or(o.contentType, "application/octet-stream"), c.value.str)
+	case "$etag", "etag":
+		result = strings.Compare(strings.Trim(o.etag, `"`), strings.Trim(c.value.str, `"`))
+	default:
+		v, ok := o.metadata[strings.TrimPrefix(c.field, "x-amz-meta-")]
+		if !ok {
+			return false
+		}
+		if c.value.isNumber {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return false
+			}
+			result = cmp.Compare(n, c.value.num)
+		} else {
+			result = strings.Compare(v, c.value.str)
+		}
+	}
+
+	switch c.op {
+	case "=":
+		return result == 0
+	case "!=", "<>":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	}
+	return false
+}
+
+// parseQuery parses an X-Tigris-Query header value.
+//
+// The grammar is a small subset of SQL WHERE clauses:
+//
+//	query      = [ "WHERE" ] expr
+//	expr       = term { "OR" term }
+//	term       = factor { "AND" factor }
+//	factor     = "NOT" factor | "(" expr ")" | field op value
+//	field      = identifier | "`" quoted identifier "`"
+//	op         = "=" | "!=" | "<>" | "<" | "<=" | ">" | ">="
+//	value      = '"' string '"' | number
+//
+// Fields are matched case-insensitively. Content-Type, Content-Length, Last-Modified,
+// Key and ETag (or their $contentType, $size, $lastModified, $key and $etag aliases)
+// refer to object properties; any other field refers to user metadata.
+func parseQuery(input string) (queryExpr, error) {
+	toks, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{toks: toks}
+	if p.peekKeyword("WHERE") {
+		p.pos++
+	}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits a query into tokens.
+func tokenize(input string) ([]token, error) {
+	var toks []token
+	rs := []rune(input)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case r == '`':
+			end := i + 1
+			for end < len(rs) && rs[end] != '`' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, errors.New("unterminated quoted identifier")
+			}
+			toks = append(toks, token{tokIdent, string(rs[i+1 : end])})
+			i = end + 1
+		case r == '"':
+			var sb strings.Builder
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' {
+					i++
+					if i == len(rs) {
+						break
+					}
+				}
+				sb.WriteRune(rs[i])
+			}
+			if i >= len(rs) {
+				return nil, errors.New("unterminated string literal")
+			}
+			toks = append(toks, token{tokString, sb.String()})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(rs) && (rs[i+1] == '=' || (r == '<' && rs[i+1] == '>')) {
+				op += string(rs[i+1])
+			}
+			if op == "!" {
+				return nil, errors.New("unexpected '!'")
+			}
+			toks = append(toks, token{tokOp, op})
+			i += len(op)
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			end := i + 1
+			for end < len(rs) && (unicode.IsDigit(rs[end]) || rs[end] == '.' || rs[end] == 'e' || rs[end] == 'E') {
+				end++
+			}
+			toks = append(toks, token{tokNumber, string(rs[i:end])})
+			i = end
+		case r == '$' || r == '_' || unicode.IsLetter(r):
+			end := i + 1
+			for end < len(rs) && (rs[end] == '_' || rs[end] == '-' || rs[end] == '.' || unicode.IsLetter(rs[end]) || unicode.IsDigit(rs[end])) {
+				end++
+			}
+			toks = append(toks, token{tokIdent, string(rs[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+
+	return toks, nil
+}
+
+// queryParser is a recursive descent parser over query tokens.
+type queryParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *queryParser) peekKeyword(kw string) bool {
+	return p.pos < len(p.toks) && p.toks[p.pos].kind == tokIdent && strings.EqualFold(p.toks[p.pos].text, kw)
+}
+
+func (p *queryParser) parseExpr() (queryExpr, error) {
+	lhs, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("OR") {
+		p.pos++
+		rhs, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		lhs = orExpr{lhs, rhs}
+	}
+	return lhs, nil
+}
+
+func (p *queryParser) parseTerm() (queryExpr, error) {
+	lhs, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("AND") {
+		p.pos++
+		rhs, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		lhs = andExpr{lhs, rhs}
+	}
+	return lhs, nil
+}
+
+func (p *queryParser) parseFactor() (queryExpr, error) {
+	if p.pos == len(p.toks) {
+		return nil, errors.New("unexpected end of query")
+	}
+
+	if p.peekKeyword("NOT") {
+		p.pos++
+		inner, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+
+	if p.toks[p.pos].kind == tokLParen {
+		p.pos++
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.toks) || p.toks[p.pos].kind != tokRParen {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	if p.pos+3 > len(p.toks) {
+		return nil, errors.New("incomplete comparison")
+	}
+
+	field, op, val := p.toks[p.pos], p.toks[p.pos+1], p.toks[p.pos+2]
+	if field.kind != tokIdent {
+		return nil, fmt.Errorf("expected field name, got %q", field.text)
+	}
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected comparison operator after %q, got %q", field.text, op.text)
+	}
+
+	var lit literal
+	switch val.kind {
+	case tokString:
+		lit.str = val.text
+	case tokNumber:
+		n, err := strconv.ParseFloat(val.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", val.text)
+		}
+		lit = literal{str: val.text, num: n, isNumber: true}
+	default:
+		return nil, fmt.Errorf("expected value after %q, got %q", op.text, val.text)
+	}
+
+	p.pos += 3
+	return comparison{field: strings.ToLower(field.text), op: op.text, value: lit}, nil
+}
@@ -0,0 +1,62 @@
+package tigristest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	obj := &object{
+		key:          "images/cat.png",
+		data:         make([]byte, 2048),
+		etag:         `"abc"`,
+		contentType:  "image/png",
+		metadata:     map[string]string{"owner": "alice", "rating": "4"},
+		lastModified: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "content type match", query: "WHERE `Content-Type` = \"image/png\"", want: true},
+		{name: "content type mismatch", query: "WHERE `Content-Type` = \"text/plain\"", want: false},
+		{name: "without WHERE", query: "`Content-Type` != \"text/plain\"", want: true},
+		{name: "size alias", query: "WHERE $size > 1024", want: true},
+		{name: "content length", query: "WHERE `Content-Length` <= 1024", want: false},
+		{name: "last modified", query: "WHERE `Last-Modified` > \"2024-01-01T00:00:00Z\"", want: true},
+		{name: "metadata", query: "WHERE owner = \"alice\"", want: true},
+		{name: "prefixed metadata", query: "WHERE `x-amz-meta-owner` = \"alice\"", want: true},
+		{name: "numeric metadata", query: "WHERE rating >= 4", want: true},
+		{name: "missing metadata", query: "WHERE missing = \"x\"", want: false},
+		{name: "and or precedence", query: "WHERE owner = \"bob\" OR owner = \"alice\" AND $size > 1", want: true},
+		{name: "not and parens", query: "WHERE NOT (owner = \"alice\")", want: false},
+		{name: "escaped string", query: `WHERE $key != "say \"hi\""`, want: true},
+		{name: "missing value", query: "WHERE owner =", wantErr: true},
+		{name: "missing operator", query: "WHERE owner \"alice\"", wantErr: true},
+		{name: "unterminated string", query: "WHERE owner = \"alice", wantErr: true},
+		{name: "unbalanced parens", query: "WHERE (owner = \"alice\"", wantErr: true},
+		{name: "trailing tokens", query: "WHERE owner = \"alice\" owner", wantErr: true},
+		{name: "empty", query: "WHERE", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parseQuery(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseQuery(%q) expected error, got nil", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQuery(%q) unexpected error: %v", tt.query, err)
+			}
+			if got := expr.eval(obj); got != tt.want {
+				t.Errorf("parseQuery(%q).eval() = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,547 @@
+package tigristest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	storage "github.com/tigrisdata/storage-go"
+	"github.com/tigrisdata/storage-go/tigrisheaders"
+	"github.com/tigrisdata/storage-go/tigristest"
+)
+
+// newClient starts a fake server and returns a storage client pointed at it.
+func newClient(t *testing.T) *storage.Client {
+	t.Helper()
+
+	srv := tigristest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := storage.New(context.Background(),
+		storage.WithEndpoint(srv.URL),
+		storage.WithPathStyle(true),
+		storage.WithAccessKeypair("test", "test"),
+	)
+	if err != nil {
+		t.Fatalf("storage.New() failed: %v", err)
+	}
+	return client
+}
+
+// errorCode returns the S3 error code of err, or "" if it is not an API error.
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+func putString(t *testing.T, client *storage.Client, bucket, key, body string, opts ...func(*s3.Options)) *s3.PutObjectOutput {
+	t.Helper()
+	out, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   strings.NewReader(body),
+	}, opts...)
+	if err != nil {
+		t.Fatalf("PutObject(%s) failed: %v", key, err)
+	}
+	return out
+}
+
+func getString(t *testing.T, client *storage.Client, bucket, key string, opts ...func(*s3.Options)) string {
+	t.Helper()
+	out, err := client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, opts...)
+	if err != nil {
+		t.Fatalf("GetObject(%s) failed: %v", key, err)
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		t.Fatalf("reading %s failed: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestServer_objects(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	putString(t, client, "bucket", "dir/a.txt", "hello", tigrisheaders.WithHeader("X-Amz-Meta-Owner", "alice"))
+	putString(t, client, "bucket", "dir/b.txt", "world")
+	putString(t, client, "bucket", "top.txt", "top")
+
+	if got := getString(t, client, "bucket", "dir/a.txt"); got != "hello" {
+		t.Errorf("GetObject() = %q, want %q", got, "hello")
+	}
+
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("dir/a.txt")})
+	if err != nil {
+		t.Fatalf("HeadObject() failed: %v", err)
+	}
+	if head.Metadata["owner"] != "alice" {
+		t.Errorf("HeadObject() metadata = %v, want owner=alice", head.Metadata)
+	}
+
+	rng, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("dir/a.txt"), Range: aws.String("bytes=1-3")})
+	if err != nil {
+		t.Fatalf("ranged GetObject() failed: %v", err)
+	}
+	data, _ := io.ReadAll(rng.Body)
+	rng.Body.Close()
+	if string(data) != "ell" || aws.ToString(rng.ContentRange) != "bytes 1-3/5" {
+		t.Errorf("ranged GetObject() = %q (%s), want %q (bytes 1-3/5)", data, aws.ToString(rng.ContentRange), "ell")
+	}
+
+	list, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String("bucket"), Delimiter: aws.String("/")})
+	if err != nil {
+		t.Fatalf("ListObjectsV2() failed: %v", err)
+	}
+	if len(list.Contents) != 1 || aws.ToString(list.Contents[0].Key) != "top.txt" {
+		t.Errorf("ListObjectsV2() contents = %v, want [top.txt]", list.Contents)
+	}
+	if len(list.CommonPrefixes) != 1 || aws.ToString(list.CommonPrefixes[0].Prefix) != "dir/" {
+		t.Errorf("ListObjectsV2() common prefixes = %v, want [dir/]", list.CommonPrefixes)
+	}
+
+	var keys []string
+	var token *string
+	for {
+		page, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String("bucket"), MaxKeys: aws.Int32(2), ContinuationToken: token})
+		if err != nil {
+			t.Fatalf("paginated ListObjectsV2() failed: %v", err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+		if !aws.ToBool(page.IsTruncated) {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	if strings.Join(keys, ",") != "dir/a.txt,dir/b.txt,top.txt" {
+		t.Errorf("paginated keys = %v", keys)
+	}
+
+	if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String("bucket"), Key: aws.String("top.txt")}); err != nil {
+		t.Fatalf("DeleteObject() failed: %v", err)
+	}
+	_, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("top.txt")})
+	var nsk *types.NoSuchKey
+	if !errors.As(err, &nsk) {
+		t.Errorf("GetObject() after delete error = %v, want NoSuchKey", err)
+	}
+}
+
+func TestServer_conditionals(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	first := putString(t, client, "bucket", "lock", "v1", tigrisheaders.WithCreateObjectIfNotExists())
+
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("lock"),
+		Body:   strings.NewReader("v2"),
+	}, tigrisheaders.WithCreateObjectIfNotExists())
+	if got := errorCode(err); got != "PreconditionFailed" {
+		t.Errorf("second create-if-absent error code = %q, want PreconditionFailed", got)
+	}
+
+	putString(t, client, "bucket", "lock", "v2", tigrisheaders.WithIfEtagMatches(aws.ToString(first.ETag)))
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("lock"),
+		Body:   strings.NewReader("v3"),
+	}, tigrisheaders.WithIfEtagMatches(aws.ToString(first.ETag)))
+	if got := errorCode(err); got != "PreconditionFailed" {
+		t.Errorf("stale If-Match error code = %q, want PreconditionFailed", got)
+	}
+
+	if got := getString(t, client, "bucket", "lock", tigrisheaders.WithCompareAndSwap()); got != "v2" {
+		t.Errorf("GetObject() = %q, want v2", got)
+	}
+}
+
+func TestServer_query(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	for key, ct := range map[string]string{"a.png": "image/png", "b.js": "text/javascript", "c.png": "image/png"} {
+		if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String("bucket"),
+			Key:         aws.String(key),
+			Body:        bytes.NewReader([]byte(key)),
+			ContentType: aws.String(ct),
+		}); err != nil {
+			t.Fatalf("PutObject(%s) failed: %v", key, err)
+		}
+	}
+
+	list, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String("bucket")},
+		tigrisheaders.WithQuery("WHERE `Content-Type` = \"image/png\""))
+	if err != nil {
+		t.Fatalf("ListObjectsV2() failed: %v", err)
+	}
+	if len(list.Contents) != 2 {
+		t.Errorf("ListObjectsV2() with query returned %d objects, want 2", len(list.Contents))
+	}
+
+	_, err = client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String("bucket")},
+		tigrisheaders.WithQuery("WHERE `Content-Type` ="))
+	if got := errorCode(err); got != "InvalidArgument" {
+		t.Errorf("invalid query error code = %q, want InvalidArgument", got)
+	}
+}
+
+func TestServer_snapshotsAndForks(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateSnapshotEnabledBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("source")}); err != nil {
+		t.Fatalf("CreateSnapshotEnabledBucket() failed: %v", err)
+	}
+
+	putString(t, client, "source", "file.txt", "before")
+
+	if _, err := client.CreateBucketSnapshot(ctx, "before change", &s3.CreateBucketInput{Bucket: aws.String("source")}); err != nil {
+		t.Fatalf("CreateBucketSnapshot() failed: %v", err)
+	}
+
+	putString(t, client, "source", "file.txt", "after")
+
+	snaps, err := client.ListBucketSnapshots(ctx, "source")
+	if err != nil {
+		t.Fatalf("ListBucketSnapshots() failed: %v", err)
+	}
+	if len(snaps.Buckets) != 1 {
+		t.Fatalf("ListBucketSnapshots() returned %d snapshots, want 1", len(snaps.Buckets))
+	}
+	version, desc, _ := strings.Cut(aws.ToString(snaps.Buckets[0].Name), "; name=")
+	if desc != "before+change" {
+		t.Errorf("snapshot description = %q, want %q", desc, "before+change")
+	}
+
+	if got := getString(t, client, "source", "file.txt", tigrisheaders.WithSnapshotVersion(version)); got != "before" {
+		t.Errorf("GetObject() from snapshot = %q, want before", got)
+	}
+
+	if _, err := client.CreateBucketFork(ctx, "source", "fork", tigrisheaders.WithSnapshotVersion(version)); err != nil {
+		t.Fatalf("CreateBucketFork() failed: %v", err)
+	}
+	if got := getString(t, client, "fork", "file.txt"); got != "before" {
+		t.Errorf("GetObject() from fork = %q, want before", got)
+	}
+
+	info, err := client.HeadBucketForkOrSnapshot(ctx, &s3.HeadBucketInput{Bucket: aws.String("fork")})
+	if err != nil {
+		t.Fatalf("HeadBucketForkOrSnapshot() failed: %v", err)
+	}
+	if info.SourceBucket != "source" || info.SourceBucketSnapshot != version || !info.SnapshotsEnabled {
+		t.Errorf("HeadBucketForkOrSnapshot(fork) = %+v", info)
+	}
+
+	info, err = client.HeadBucketForkOrSnapshot(ctx, &s3.HeadBucketInput{Bucket: aws.String("source")})
+	if err != nil {
+		t.Fatalf("HeadBucketForkOrSnapshot() failed: %v", err)
+	}
+	if !info.IsForkParent {
+		t.Errorf("HeadBucketForkOrSnapshot(source).IsForkParent = false, want true")
+	}
+}
+
+func TestServer_rename(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	putString(t, client, "bucket", "old.txt", "data")
+
+	if _, err := client.RenameObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String("bucket"),
+		CopySource: aws.String("bucket/old.txt"),
+		Key:        aws.String("new.txt"),
+	}); err != nil {
+		t.Fatalf("RenameObject() failed: %v", err)
+	}
+
+	if got := getString(t, client, "bucket", "new.txt"); got != "data" {
+		t.Errorf("GetObject(new.txt) = %q, want data", got)
+	}
+
+	_, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("old.txt")})
+	var nf *types.NotFound
+	if !errors.As(err, &nf) {
+		t.Errorf("HeadObject(old.txt) error = %v, want NotFound", err)
+	}
+}
+
+func TestServer_multipart(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	mpu, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{Bucket: aws.String("bucket"), Key: aws.String("big")})
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload() failed: %v", err)
+	}
+
+	var parts []types.CompletedPart
+	for i, chunk := range []string{"part one ", "part two"} {
+		out, err := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String("bucket"),
+			Key:        aws.String("big"),
+			UploadId:   mpu.UploadId,
+			PartNumber: aws.Int32(int32(i + 1)),
+			Body:       strings.NewReader(chunk),
+		})
+		if err != nil {
+			t.Fatalf("UploadPart(%d) failed: %v", i+1, err)
+		}
+		parts = append(parts, types.CompletedPart{ETag: out.ETag, PartNumber: aws.Int32(int32(i + 1))})
+	}
+
+	done, err := client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String("bucket"),
+		Key:             aws.String("big"),
+		UploadId:        mpu.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload() failed: %v", err)
+	}
+	if !strings.HasSuffix(aws.ToString(done.ETag), `-2"`) {
+		t.Errorf("multipart ETag = %s, want suffix -2", aws.ToString(done.ETag))
+	}
+
+	if got := getString(t, client, "bucket", "big"); got != "part one part two" {
+		t.Errorf("GetObject() = %q, want %q", got, "part one part two")
+	}
+}
+
+func TestServer_multipartConditional(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	existing := putString(t, client, "bucket", "big", "old")
+
+	// upload starts a multipart upload of a single part and returns its ID and parts.
+	upload := func() (*string, []types.CompletedPart) {
+		t.Helper()
+
+		mpu, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{Bucket: aws.String("bucket"), Key: aws.String("big")})
+		if err != nil {
+			t.Fatalf("CreateMultipartUpload() failed: %v", err)
+		}
+		out, err := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String("bucket"),
+			Key:        aws.String("big"),
+			UploadId:   mpu.UploadId,
+			PartNumber: aws.Int32(1),
+			Body:       strings.NewReader("new"),
+		})
+		if err != nil {
+			t.Fatalf("UploadPart() failed: %v", err)
+		}
+		return mpu.UploadId, []types.CompletedPart{{ETag: out.ETag, PartNumber: aws.Int32(1)}}
+	}
+
+	complete := func(uploadID *string, parts []types.CompletedPart, cond func(*s3.Options)) error {
+		_, err := client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("bucket"),
+			Key:             aws.String("big"),
+			UploadId:        uploadID,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		}, cond)
+		return err
+	}
+
+	_, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{Bucket: aws.String("bucket"), Key: aws.String("big")},
+		tigrisheaders.WithCreateObjectIfNotExists())
+	if code := errorCode(err); code != "NotImplemented" {
+		t.Errorf("CreateMultipartUpload() with If-Match error code = %q, want NotImplemented", code)
+	}
+
+	id, parts := upload()
+	if code := errorCode(complete(id, parts, tigrisheaders.WithCreateObjectIfNotExists())); code != "PreconditionFailed" {
+		t.Errorf("CompleteMultipartUpload() creating an existing object error code = %q, want PreconditionFailed", code)
+	}
+	if code := errorCode(complete(id, parts, tigrisheaders.WithIfEtagMatches(`"stale"`))); code != "PreconditionFailed" {
+		t.Errorf("CompleteMultipartUpload() with a stale ETag error code = %q, want PreconditionFailed", code)
+	}
+	if got := getString(t, client, "bucket", "big"); got != "old" {
+		t.Fatalf("GetObject() after failed conditions = %q, want old", got)
+	}
+
+	if err := complete(id, parts, tigrisheaders.WithIfEtagMatches(aws.ToString(existing.ETag))); err != nil {
+		t.Fatalf("CompleteMultipartUpload() with the current ETag failed: %v", err)
+	}
+	if got := getString(t, client, "bucket", "big"); got != "new" {
+		t.Errorf("GetObject() = %q, want new", got)
+	}
+}
+
+func TestServer_deleteBucket(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	putString(t, client, "bucket", "file", "data")
+
+	_, err := client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String("bucket")})
+	if got := errorCode(err); got != "BucketNotEmpty" {
+		t.Errorf("DeleteBucket() on non-empty bucket error code = %q, want BucketNotEmpty", got)
+	}
+
+	if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String("bucket"), Key: aws.String("file")}); err != nil {
+		t.Fatalf("DeleteObject() failed: %v", err)
+	}
+	if _, err := client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("DeleteBucket() failed: %v", err)
+	}
+}
+
+func TestServer_listObjectVersions(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+	putString(t, client, "bucket", "a/1", "one")
+	putString(t, client, "bucket", "a/2", "two")
+	putString(t, client, "bucket", "b/3", "three")
+
+	resp, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+		Bucket: aws.String("bucket"),
+		Prefix: aws.String("a/"),
+	})
+	if err != nil {
+		t.Fatalf("ListObjectVersions() failed: %v", err)
+	}
+
+	var got []string
+	for _, v := range resp.Versions {
+		got = append(got, aws.ToString(v.Key)+"@"+aws.ToString(v.VersionId))
+		if !aws.ToBool(v.IsLatest) {
+			t.Errorf("version of %s is not the latest", aws.ToString(v.Key))
+		}
+	}
+	if want := []string{"a/1@null", "a/2@null"}; !slices.Equal(got, want) {
+		t.Errorf("ListObjectVersions() = %q, want %q", got, want)
+	}
+}
+
+func TestServer_post(t *testing.T) {
+	ctx := context.Background()
+	srv := tigristest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := storage.New(ctx,
+		storage.WithEndpoint(srv.URL),
+		storage.WithPathStyle(true),
+		storage.WithAccessKeypair("test", "test"),
+	)
+	if err != nil {
+		t.Fatalf("storage.New() failed: %v", err)
+	}
+	if _, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("CreateBucket() failed: %v", err)
+	}
+
+	policy := base64.StdEncoding.EncodeToString([]byte(`{
+		"expiration": "` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `",
+		"conditions": [
+			{"bucket": "bucket"},
+			["starts-with", "$key", "uploads/"],
+			{"success_action_status": "201"},
+			["content-length-range", 1, 10]
+		]
+	}`))
+
+	post := func(fields map[string]string, data string) *http.Response {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for k, v := range fields {
+			mw.WriteField(k, v)
+		}
+		fw, _ := mw.CreateFormFile("file", "hello.txt")
+		io.WriteString(fw, data)
+		mw.Close()
+
+		resp, err := http.Post(srv.URL+"/bucket", mw.FormDataContentType(), &body)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := post(map[string]string{"key": "uploads/${filename}", "policy": policy, "success_action_status": "201"}, "hello")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST status = %d, want 201", resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "<Key>uploads/hello.txt</Key>") {
+		t.Errorf("POST response = %s, want the uploaded key", body)
+	}
+	if got := getString(t, client, "bucket", "uploads/hello.txt"); got != "hello" {
+		t.Errorf("GetObject() = %q, want %q", got, "hello")
+	}
+
+	for _, tt := range []struct {
+		name   string
+		fields map[string]string
+		data   string
+		want   int
+	}{
+		{"key outside prefix", map[string]string{"key": "other/x", "policy": policy, "success_action_status": "201"}, "hello", http.StatusForbidden},
+		{"too large", map[string]string{"key": "uploads/x", "policy": policy, "success_action_status": "201"}, "hello, world", http.StatusBadRequest},
+		{"uncovered field", map[string]string{"key": "uploads/x", "policy": policy, "success_action_status": "201", "acl": "public-read"}, "hello", http.StatusForbidden},
+		{"no policy", map[string]string{"key": "uploads/x"}, "hello", http.StatusBadRequest},
+		{"no key", map[string]string{"policy": policy}, "hello", http.StatusBadRequest},
+	} {
+		if resp := post(tt.fields, tt.data); resp.StatusCode != tt.want {
+			t.Errorf("POST with %s status = %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+}